@@ -4,3 +4,5 @@ import "errors"
 
 
 var ErrIterationsExhausted = errors.New("iterations exhausted, and a result could not be found")
+var ErrZeroPolynomial = errors.New("the zero polynomial has infinitely many roots")
+var ErrNonPositiveEpsilon = errors.New("the epsilon must be a positive number")
@@ -0,0 +1,399 @@
+package goals
+
+import (
+	"math/big"
+	"sort"
+	"github.com/universe-10th/calculus/ops"
+	"github.com/universe-10th/calculus/sets"
+	"github.com/universe-10th/calculus/core/support"
+	goalErrors "github.com/universe-10th/calculus/core/goals/errors"
+)
+
+
+// PolynomialRoot is a real root of a polynomial, along with its multiplicity.
+// Exact roots are *big.(Int, Rat) values, while the other ones are *big.Float
+// approximations, being at most epsilon apart from the actual root.
+type PolynomialRoot struct {
+	Value        sets.Number
+	Multiplicity int
+	Exact        bool
+}
+
+
+// The largest prime factor the rational root theorem will look for, when
+// factoring the constant and main coefficients. Coefficients having larger
+// prime factors will not be tested for rational roots: their roots will
+// be isolated and approximated instead.
+const maxTrialDivisor = 1 << 20
+
+
+func toRat(number sets.Number) *big.Rat {
+	switch value := number.(type) {
+	case *big.Int:
+		return big.NewRat(0, 1).SetInt(value)
+	case *big.Rat:
+		return big.NewRat(0, 1).Set(value)
+	case *big.Float:
+		// Floats are dyadic rationals, so this conversion is exact.
+		result, _ := value.Rat(nil)
+		return result
+	default:
+		panic("cannot convert a non-*big.(Int, Float, Rat) value to a rational")
+	}
+}
+
+
+func normalizedRat(value *big.Rat) sets.Number {
+	if value.IsInt() {
+		return big.NewInt(0).Set(value.Num())
+	}
+	return value
+}
+
+
+func signAt(polynomial support.Polynomial, value *big.Rat) int {
+	return toRat(polynomial.Evaluate(value)).Sign()
+}
+
+
+// exactPolynomial converts all the coefficients to exact rationals.
+func exactPolynomial(polynomial support.Polynomial) support.Polynomial {
+	coefficients := polynomial.Coefficients()
+	exact := make([]interface{}, len(coefficients))
+	for index, coefficient := range coefficients {
+		exact[index] = toRat(coefficient)
+	}
+	return support.NewPolynomial(exact...)
+}
+
+
+// squareFreeFactors splits an exact polynomial into square-free factors by Yun's
+// algorithm. Each factor is returned by the multiplicity of its roots.
+func squareFreeFactors(polynomial support.Polynomial) map[int]support.Polynomial {
+	factors := map[int]support.Polynomial{}
+	common := polynomial.GCD(polynomial.Derivative())
+	current, _, _ := polynomial.DivMod(common)
+	for multiplicity := 1; current.Degree() > 0; multiplicity++ {
+		repeated := current.GCD(common)
+		factor, _, _ := current.DivMod(repeated)
+		if factor.Degree() > 0 {
+			factors[multiplicity] = factor
+		}
+		current = repeated
+		common, _, _ = common.DivMod(repeated)
+	}
+	return factors
+}
+
+
+// divisors returns the positive divisors of a non-zero integer, or false if
+// it has prime factors beyond maxTrialDivisor.
+func divisors(number *big.Int) ([]*big.Int, bool) {
+	remaining := big.NewInt(0).Abs(number)
+	result := []*big.Int{big.NewInt(1)}
+	quotient, modulus := big.NewInt(0), big.NewInt(0)
+	addFactor := func(prime *big.Int, power int) {
+		current := len(result)
+		factor := big.NewInt(1)
+		for times := 0; times < power; times++ {
+			factor = big.NewInt(0).Mul(factor, prime)
+			for _, divisor := range result[0:current] {
+				result = append(result, big.NewInt(0).Mul(divisor, factor))
+			}
+		}
+	}
+	for candidate := int64(2); candidate <= maxTrialDivisor; candidate++ {
+		prime := big.NewInt(candidate)
+		if big.NewInt(0).Mul(prime, prime).Cmp(remaining) > 0 {
+			break
+		}
+		power := 0
+		for quotient.QuoRem(remaining, prime, modulus); modulus.Sign() == 0; quotient.QuoRem(remaining, prime, modulus) {
+			remaining.Set(quotient)
+			power++
+		}
+		if power > 0 {
+			addFactor(prime, power)
+		}
+	}
+	if remaining.Cmp(big.NewInt(1)) > 0 {
+		// What remains is a prime unless it has two factors beyond the limit.
+		limit := big.NewInt(maxTrialDivisor)
+		if remaining.Cmp(limit.Mul(limit, limit)) > 0 {
+			return nil, false
+		}
+		addFactor(remaining, 1)
+	}
+	return result, true
+}
+
+
+// rationalRoots finds the rational roots of an exact, square-free, polynomial
+// by the rational root theorem, and returns them along with the polynomial
+// remaining after dividing by their factors.
+func rationalRoots(polynomial support.Polynomial) ([]*big.Rat, support.Polynomial) {
+	roots := []*big.Rat{}
+	if ops.IsZero(polynomial.Coefficient(0)) {
+		roots = append(roots, big.NewRat(0, 1))
+		polynomial, _, _ = polynomial.DivMod(support.NewPolynomial(1, 0))
+	}
+	if polynomial.Degree() < 1 {
+		return roots, polynomial
+	}
+	// Scale the coefficients to integers by the lcm of their denominators.
+	scale := big.NewInt(1)
+	for _, coefficient := range polynomial.Coefficients() {
+		denominator := toRat(coefficient).Denom()
+		gcd := big.NewInt(0).GCD(nil, nil, scale, denominator)
+		scale.Mul(scale, big.NewInt(0).Quo(denominator, gcd))
+	}
+	constant := big.NewRat(0, 1).Mul(toRat(polynomial.Coefficient(0)), big.NewRat(0, 1).SetInt(scale)).Num()
+	leading := big.NewRat(0, 1).Mul(toRat(polynomial.LeadingCoefficient()), big.NewRat(0, 1).SetInt(scale)).Num()
+	numerators, ok := divisors(constant)
+	if !ok {
+		return roots, polynomial
+	}
+	denominators, ok := divisors(leading)
+	if !ok {
+		return roots, polynomial
+	}
+	for _, numerator := range numerators {
+		for _, denominator := range denominators {
+			if big.NewInt(0).GCD(nil, nil, numerator, denominator).Cmp(big.NewInt(1)) != 0 {
+				continue
+			}
+			for _, sign := range []int64{1, -1} {
+				candidate := big.NewRat(0, 1).SetFrac(big.NewInt(0).Mul(numerator, big.NewInt(sign)), denominator)
+				if polynomial.Degree() >= 1 && signAt(polynomial, candidate) == 0 {
+					roots = append(roots, candidate)
+					polynomial, _, _ = polynomial.DivMod(support.NewPolynomial(1, ops.Neg(candidate)))
+				}
+			}
+		}
+	}
+	return roots, polynomial
+}
+
+
+// rootsPrecision tells the precision a root must have to be represented within
+// the given epsilon, given the magnitude of the roots.
+func rootsPrecision(epsilon *big.Float, bound *big.Rat) uint {
+	magnitude := new(big.Float).SetRat(bound).MantExp(nil)
+	precision := magnitude - epsilon.MantExp(nil) + 16
+	if precision < 53 {
+		precision = 53
+	}
+	return uint(precision)
+}
+
+
+// quadraticRoots computes the real roots of an exact polynomial of degree 2
+// by its closed form. Roots are exact when the discriminant is a perfect square.
+func quadraticRoots(polynomial support.Polynomial, precision uint) []PolynomialRoot {
+	a := toRat(polynomial.Coefficient(2))
+	b := toRat(polynomial.Coefficient(1))
+	c := toRat(polynomial.Coefficient(0))
+	discriminant := big.NewRat(0, 1).Mul(b, b)
+	discriminant.Sub(discriminant, big.NewRat(0, 1).Mul(big.NewRat(4, 1), big.NewRat(0, 1).Mul(a, c)))
+	switch discriminant.Sign() {
+	case -1:
+		return nil
+	case 0:
+		root := big.NewRat(0, 1).Quo(big.NewRat(0, 1).Neg(b), big.NewRat(0, 1).Mul(big.NewRat(2, 1), a))
+		return []PolynomialRoot{{normalizedRat(root), 1, true}}
+	}
+	numeratorRoot := big.NewInt(0).Sqrt(discriminant.Num())
+	denominatorRoot := big.NewInt(0).Sqrt(discriminant.Denom())
+	if big.NewInt(0).Mul(numeratorRoot, numeratorRoot).Cmp(discriminant.Num()) == 0 &&
+	   big.NewInt(0).Mul(denominatorRoot, denominatorRoot).Cmp(discriminant.Denom()) == 0 {
+		root := big.NewRat(0, 1).SetFrac(numeratorRoot, denominatorRoot)
+		twiceA := big.NewRat(0, 1).Mul(big.NewRat(2, 1), a)
+		first := big.NewRat(0, 1).Quo(big.NewRat(0, 1).Sub(big.NewRat(0, 1).Neg(b), root), twiceA)
+		second := big.NewRat(0, 1).Quo(big.NewRat(0, 1).Add(big.NewRat(0, 1).Neg(b), root), twiceA)
+		return []PolynomialRoot{{normalizedRat(first), 1, true}, {normalizedRat(second), 1, true}}
+	}
+	// q = -(b + sign(b) * sqrt(discriminant)) / 2 avoids the cancellation
+	// of the usual formula: the roots are q / a and c / q.
+	root := new(big.Float).SetPrec(precision).SetRat(discriminant)
+	root.Sqrt(root)
+	floatB := new(big.Float).SetPrec(precision).SetRat(b)
+	if b.Sign() < 0 {
+		root.Neg(root)
+	}
+	q := new(big.Float).SetPrec(precision).Add(floatB, root)
+	q.Quo(q, big.NewFloat(-2))
+	first := new(big.Float).SetPrec(precision).Quo(q, new(big.Float).SetPrec(precision).SetRat(a))
+	second := new(big.Float).SetPrec(precision).Quo(new(big.Float).SetPrec(precision).SetRat(c), q)
+	return []PolynomialRoot{{first, 1, false}, {second, 1, false}}
+}
+
+
+// SturmSequence computes the Sturm sequence of a polynomial: p, p', and then the
+// negated remainder of dividing each element by the next one, until it is zero.
+func SturmSequence(polynomial support.Polynomial) []support.Polynomial {
+	sequence := []support.Polynomial{}
+	if polynomial.IsZero() {
+		return sequence
+	}
+	sequence = append(sequence, polynomial)
+	current := polynomial.Derivative()
+	for !current.IsZero() {
+		previous := sequence[len(sequence) - 1]
+		sequence = append(sequence, current)
+		_, remainder, _ := previous.DivMod(current)
+		current = remainder.Scale(big.NewInt(-1))
+	}
+	return sequence
+}
+
+
+// signChanges counts the sign changes (zeros are skipped) of a Sturm sequence
+// evaluated at the given value.
+func signChanges(sequence []support.Polynomial, value *big.Rat) int {
+	changes := 0
+	last := 0
+	for _, element := range sequence {
+		if sign := signAt(element, value); sign != 0 {
+			if last != 0 && sign != last {
+				changes++
+			}
+			last = sign
+		}
+	}
+	return changes
+}
+
+
+// CountRealRoots counts the distinct real roots of a polynomial lying in the
+// (lower, upper] interval, by Sturm's theorem. The bounds must be primitive
+// or *big.(Int, Rat, Float) numbers.
+func CountRealRoots(polynomial support.Polynomial, lower, upper interface{}) (int, error) {
+	if polynomial.IsZero() {
+		return 0, goalErrors.ErrZeroPolynomial
+	}
+	wrappedLower, _ := sets.Wrap(lower)
+	wrappedUpper, _ := sets.Wrap(upper)
+	sequence := SturmSequence(exactPolynomial(polynomial))
+	return signChanges(sequence, toRat(wrappedLower)) - signChanges(sequence, toRat(wrappedUpper)), nil
+}
+
+
+// rootsBound computes Cauchy's bound: all the roots are strictly less than it, in absolute value.
+func rootsBound(polynomial support.Polynomial) *big.Rat {
+	leading := toRat(polynomial.LeadingCoefficient())
+	maxRatio := big.NewRat(0, 1)
+	for power := 0; power < polynomial.Degree(); power++ {
+		ratio := big.NewRat(0, 1).Quo(toRat(polynomial.Coefficient(power)), leading)
+		ratio.Abs(ratio)
+		if ratio.Cmp(maxRatio) > 0 {
+			maxRatio = ratio
+		}
+	}
+	return maxRatio.Add(maxRatio, big.NewRat(1, 1))
+}
+
+
+// refinedRoot bisects an interval (lower, upper] having exactly one simple root
+// of the polynomial, until its width is not greater than the epsilon.
+func refinedRoot(polynomial support.Polynomial, lower, upper *big.Rat, epsilon *big.Rat, precision uint) PolynomialRoot {
+	upperSign := signAt(polynomial, upper)
+	if upperSign == 0 {
+		return PolynomialRoot{normalizedRat(upper), 1, true}
+	}
+	width := big.NewRat(0, 1)
+	half := big.NewRat(1, 2)
+	for width.Sub(upper, lower).Cmp(epsilon) > 0 {
+		middle := big.NewRat(0, 1).Add(lower, upper)
+		middle.Mul(middle, half)
+		switch signAt(polynomial, middle) {
+		case 0:
+			return PolynomialRoot{normalizedRat(middle), 1, true}
+		case upperSign:
+			upper = middle
+		default:
+			lower = middle
+		}
+	}
+	middle := big.NewRat(0, 1).Add(lower, upper)
+	middle.Mul(middle, half)
+	return PolynomialRoot{new(big.Float).SetPrec(precision).SetRat(middle), 1, false}
+}
+
+
+// isolatedRoots isolates the real roots of an exact, square-free, polynomial
+// by bisecting the interval given by Cauchy's bound, counting the roots of each
+// half by Sturm's theorem, and then refines each of them by bisection.
+func isolatedRoots(polynomial support.Polynomial, epsilon *big.Float) []PolynomialRoot {
+	sequence := SturmSequence(polynomial)
+	bound := rootsBound(polynomial)
+	precision := rootsPrecision(epsilon, bound)
+	exactEpsilon := toRat(epsilon)
+	type interval struct {
+		lower, upper             *big.Rat
+		lowerChanges, upperChanges int
+	}
+	lower := big.NewRat(0, 1).Neg(bound)
+	pending := []interval{{lower, bound, signChanges(sequence, lower), signChanges(sequence, bound)}}
+	roots := []PolynomialRoot{}
+	for len(pending) > 0 {
+		current := pending[len(pending) - 1]
+		pending = pending[0:len(pending) - 1]
+		switch count := current.lowerChanges - current.upperChanges; {
+		case count == 1:
+			roots = append(roots, refinedRoot(polynomial, current.lower, current.upper, exactEpsilon, precision))
+		case count > 1:
+			middle := big.NewRat(0, 1).Add(current.lower, current.upper)
+			middle.Mul(middle, big.NewRat(1, 2))
+			middleChanges := signChanges(sequence, middle)
+			pending = append(pending,
+				interval{current.lower, middle, current.lowerChanges, middleChanges},
+				interval{middle, current.upper, middleChanges, current.upperChanges},
+			)
+		}
+	}
+	return roots
+}
+
+
+// PolynomialRoots finds all the real roots of a polynomial, along with their
+// multiplicities, sorted in ascending order. Float coefficients are taken as
+// their exact (rational) values. The polynomial is split in square-free factors
+// and, for each one, the rational roots are found exactly by the rational root
+// theorem. The remaining roots are found by closed forms, for degrees up to 2,
+// or isolated by Sturm sequences. Non-rational roots are approximated, in big
+// float arithmetic, within the given epsilon (e.g. diff.Epsilon(20)).
+func PolynomialRoots(polynomial support.Polynomial, epsilon *big.Float) ([]PolynomialRoot, error) {
+	if polynomial.IsZero() {
+		return nil, goalErrors.ErrZeroPolynomial
+	} else if epsilon == nil || epsilon.Sign() <= 0 {
+		return nil, goalErrors.ErrNonPositiveEpsilon
+	}
+	exact := exactPolynomial(polynomial)
+	precision := rootsPrecision(epsilon, rootsBound(exact))
+	roots := []PolynomialRoot{}
+	for multiplicity, factor := range squareFreeFactors(exact) {
+		rational, remaining := rationalRoots(factor)
+		factorRoots := []PolynomialRoot{}
+		for _, root := range rational {
+			factorRoots = append(factorRoots, PolynomialRoot{normalizedRat(root), 1, true})
+		}
+		switch remaining.Degree() {
+		case 0:
+		case 1:
+			root := big.NewRat(0, 1).Quo(toRat(remaining.Coefficient(0)), toRat(remaining.Coefficient(1)))
+			factorRoots = append(factorRoots, PolynomialRoot{normalizedRat(root.Neg(root)), 1, true})
+		case 2:
+			factorRoots = append(factorRoots, quadraticRoots(remaining, precision)...)
+		default:
+			factorRoots = append(factorRoots, isolatedRoots(remaining, epsilon)...)
+		}
+		for _, root := range factorRoots {
+			root.Multiplicity = multiplicity
+			roots = append(roots, root)
+		}
+	}
+	sort.Slice(roots, func(i, j int) bool {
+		return ops.Cmp(roots[i].Value, roots[j].Value) < 0
+	})
+	return roots, nil
+}
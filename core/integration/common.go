@@ -0,0 +1,79 @@
+package integration
+
+import (
+	"errors"
+	"math/big"
+	"github.com/universe-10th/calculus/sets"
+	integrationErrors "github.com/universe-10th/calculus/core/integration/errors"
+)
+
+
+// An evaluator wraps the integrated function, converting its results to
+// floats of the working precision and keeping track of the evaluations.
+type evaluator struct {
+	function  func(*big.Float) (sets.Number, error)
+	remaining uint32
+	precision uint
+}
+
+
+func (evaluator *evaluator) at(argument *big.Float) (*big.Float, error) {
+	if evaluator.remaining == 0 {
+		return nil, integrationErrors.ErrEvaluationsExhausted
+	}
+	evaluator.remaining--
+	if result, err := evaluator.function(argument); err != nil {
+		return nil, err
+	} else {
+		// This function may panic, but we're covered in the parent call.
+		return evaluator.newFloat().Set(sets.UpCastOneTo(result, sets.R).(*big.Float)), nil
+	}
+}
+
+
+func (evaluator *evaluator) newFloat() *big.Float {
+	return new(big.Float).SetPrec(evaluator.precision)
+}
+
+
+// workingPrecision tells the precision the computations will be done with: the
+// one of the bounds, or enough bits to represent the epsilon (plus a margin for
+// rounding errors), whichever is greater. It is at least the float64 precision.
+func workingPrecision(lower, upper, epsilon *big.Float) uint {
+	precision := uint(53)
+	if lower.Prec() > precision {
+		precision = lower.Prec()
+	}
+	if upper.Prec() > precision {
+		precision = upper.Prec()
+	}
+	if bits := 32 - epsilon.MantExp(nil); bits > 0 && uint(bits) > precision {
+		precision = uint(bits)
+	}
+	return precision
+}
+
+
+// newEvaluator validates the arguments of a quadrature and builds the evaluator.
+func newEvaluator(function func(*big.Float) (sets.Number, error), lower, upper, epsilon *big.Float,
+	              maxEvaluations uint32) (*evaluator, error) {
+	if epsilon == nil || epsilon.Sign() <= 0 {
+		return nil, integrationErrors.ErrNonPositiveEpsilon
+	}
+	return &evaluator{function, maxEvaluations, workingPrecision(lower, upper, epsilon)}, nil
+}
+
+
+// recoverPanic turns a panic (e.g. a non-numeric result) into an error.
+func recoverPanic(result **big.Float, exception *error) {
+	if recovered := recover(); recovered != nil {
+		*result = nil
+		if message, ok := recovered.(string); ok {
+			*exception = errors.New(message)
+		} else if err, ok := recovered.(error); ok {
+			*exception = err
+		} else {
+			*exception = errors.New("unexpected failure while integrating")
+		}
+	}
+}
@@ -0,0 +1,7 @@
+package errors
+
+import "errors"
+
+
+var ErrEvaluationsExhausted = errors.New("evaluations exhausted, and the integral could not be estimated within the epsilon")
+var ErrNonPositiveEpsilon = errors.New("the epsilon must be a positive number")
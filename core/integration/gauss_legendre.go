@@ -0,0 +1,140 @@
+package integration
+
+import (
+	"math"
+	"math/big"
+	"github.com/universe-10th/calculus/sets"
+	integrationErrors "github.com/universe-10th/calculus/core/integration/errors"
+)
+
+
+// legendre computes the Legendre polynomial of the given degree, and its
+// derivative, at the given point (which must not be 1 or -1).
+func (evaluator *evaluator) legendre(degree int, x *big.Float) (*big.Float, *big.Float) {
+	previous := evaluator.newFloat().SetInt64(1)
+	current := evaluator.newFloat().Set(x)
+	for k := 2; k <= degree; k++ {
+		// P(k) = ((2k - 1) * x * P(k - 1) - (k - 1) * P(k - 2)) / k
+		next := evaluator.newFloat().Mul(x, current)
+		next.Mul(next, evaluator.newFloat().SetInt64(int64(2 * k - 1)))
+		next.Sub(next, evaluator.newFloat().Mul(previous, evaluator.newFloat().SetInt64(int64(k - 1))))
+		next.Quo(next, evaluator.newFloat().SetInt64(int64(k)))
+		previous, current = current, next
+	}
+	// P'(n) = n * (x * P(n) - P(n - 1)) / (x^2 - 1)
+	derivative := evaluator.newFloat().Mul(x, current)
+	derivative.Sub(derivative, previous)
+	derivative.Mul(derivative, evaluator.newFloat().SetInt64(int64(degree)))
+	denominator := evaluator.newFloat().Mul(x, x)
+	denominator.Sub(denominator, big.NewFloat(1))
+	return current, derivative.Quo(derivative, denominator)
+}
+
+
+// legendreNodes computes the nodes and weights of the Gauss-Legendre rule of the
+// given degree, in the working precision. Nodes are first guessed in float64 and
+// then refined by Newton-Raphson.
+func (evaluator *evaluator) legendreNodes(degree int) ([]*big.Float, []*big.Float) {
+	nodes := make([]*big.Float, degree)
+	weights := make([]*big.Float, degree)
+	tolerance := evaluator.newFloat().SetMantExp(big.NewFloat(1), 4 - int(evaluator.precision))
+	for index := 0; index < degree; index++ {
+		x := evaluator.newFloat().SetFloat64(math.Cos(math.Pi * (float64(index) + 0.75) / (float64(degree) + 0.5)))
+		var derivative *big.Float
+		for iteration := 0; iteration < 100; iteration++ {
+			var value *big.Float
+			value, derivative = evaluator.legendre(degree, x)
+			step := evaluator.newFloat().Quo(value, derivative)
+			x.Sub(x, step)
+			if step.Abs(step).Cmp(tolerance) <= 0 {
+				break
+			}
+		}
+		_, derivative = evaluator.legendre(degree, x)
+		// w = 2 / ((1 - x^2) * P'(x)^2)
+		weight := evaluator.newFloat().Mul(x, x)
+		weight.Sub(big.NewFloat(1), weight)
+		weight.Mul(weight, derivative)
+		weight.Mul(weight, derivative)
+		nodes[index] = x
+		weights[index] = weight.Quo(big.NewFloat(2), weight)
+	}
+	return nodes, weights
+}
+
+
+// The largest number of nodes a single Gauss-Legendre rule will have. Beyond
+// that, the interval is split in more and more panels, each one integrated by
+// a rule of this degree (computing the nodes of larger rules is too expensive).
+const maxGaussLegendreDegree = 64
+
+
+// gaussLegendre applies the Gauss-Legendre rule of the given nodes and weights,
+// on each of the given number of panels [a, b] is split in.
+func (evaluator *evaluator) gaussLegendre(nodes, weights []*big.Float, panels int, a, b *big.Float) (*big.Float, error) {
+	panelWidth := evaluator.newFloat().Sub(b, a)
+	panelWidth.Quo(panelWidth, evaluator.newFloat().SetInt64(int64(panels)))
+	halfWidth := evaluator.newFloat().Quo(panelWidth, big.NewFloat(2))
+	total := evaluator.newFloat()
+	for panel := 0; panel < panels; panel++ {
+		center := evaluator.newFloat().Mul(panelWidth, evaluator.newFloat().SetInt64(int64(panel)))
+		center.Add(center, a)
+		center.Add(center, halfWidth)
+		sum := evaluator.newFloat()
+		for index, node := range nodes {
+			argument := evaluator.newFloat().Mul(node, halfWidth)
+			if value, err := evaluator.at(argument.Add(argument, center)); err != nil {
+				return nil, err
+			} else {
+				sum.Add(sum, value.Mul(value, weights[index]))
+			}
+		}
+		total.Add(total, sum.Mul(sum, halfWidth))
+	}
+	return total, nil
+}
+
+
+// GaussLegendre estimates the definite integral of a function between two
+// bounds by Gauss-Legendre rules of 2, 4, 8, ... nodes (and, once the rules
+// reach 64 nodes, by compound rules of 2, 4, 8, ... panels), until two
+// consecutive estimates differ, at most, by the epsilon. The nodes are
+// computed in the working precision. The function will not be evaluated
+// more than the given number of times: if the estimate is not good enough
+// by then, ErrEvaluationsExhausted is returned.
+func GaussLegendre(function func(*big.Float) (sets.Number, error), lower, upper, epsilon *big.Float,
+	               maxEvaluations uint32) (result *big.Float, exception error) {
+	defer recoverPanic(&result, &exception)
+	evaluator, err := newEvaluator(function, lower, upper, epsilon, maxEvaluations)
+	if err != nil {
+		return nil, err
+	}
+	a := evaluator.newFloat().Set(lower)
+	b := evaluator.newFloat().Set(upper)
+	nodes, weights := evaluator.legendreNodes(2)
+	previous, err := evaluator.gaussLegendre(nodes, weights, 1, a, b)
+	if err != nil {
+		return nil, err
+	}
+	degree, panels := 2, 1
+	for {
+		if degree < maxGaussLegendreDegree {
+			degree *= 2
+			nodes, weights = evaluator.legendreNodes(degree)
+		} else {
+			panels *= 2
+		}
+		if uint64(degree) * uint64(panels) > uint64(evaluator.remaining) {
+			return nil, integrationErrors.ErrEvaluationsExhausted
+		}
+		current, err := evaluator.gaussLegendre(nodes, weights, panels, a, b)
+		if err != nil {
+			return nil, err
+		}
+		difference := evaluator.newFloat().Sub(current, previous)
+		if difference.Abs(difference).Cmp(epsilon) <= 0 {
+			return current, nil
+		}
+		previous = current
+	}
+}
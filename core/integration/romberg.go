@@ -0,0 +1,74 @@
+package integration
+
+import (
+	"math/big"
+	"github.com/universe-10th/calculus/sets"
+	integrationErrors "github.com/universe-10th/calculus/core/integration/errors"
+)
+
+
+// Romberg estimates the definite integral of a function between two bounds by
+// Romberg's method: the trapezoidal rule is computed with 1, 2, 4, ... intervals
+// and then extrapolated (by Richardson's method) until two consecutive diagonal
+// estimates differ, at most, by the epsilon. The function will not be evaluated
+// more than the given number of times: if the estimate is not good enough by
+// then, ErrEvaluationsExhausted is returned.
+func Romberg(function func(*big.Float) (sets.Number, error), lower, upper, epsilon *big.Float,
+	         maxEvaluations uint32) (result *big.Float, exception error) {
+	defer recoverPanic(&result, &exception)
+	evaluator, err := newEvaluator(function, lower, upper, epsilon, maxEvaluations)
+	if err != nil {
+		return nil, err
+	}
+	a := evaluator.newFloat().Set(lower)
+	b := evaluator.newFloat().Set(upper)
+	width := evaluator.newFloat().Sub(b, a)
+	fa, err := evaluator.at(a)
+	if err != nil {
+		return nil, err
+	}
+	fb, err := evaluator.at(b)
+	if err != nil {
+		return nil, err
+	}
+	// R(0, 0) = (b - a) / 2 * (f(a) + f(b))
+	first := evaluator.newFloat().Add(fa, fb)
+	first.Mul(first, width)
+	first.Quo(first, big.NewFloat(2))
+	previous := []*big.Float{first}
+	step := evaluator.newFloat().Set(width)
+	points := int64(1)
+	for row := 1; ; row++ {
+		// R(n, 0) = R(n - 1, 0) / 2 + h * sum(f(a + (2k - 1) * h)), k = 1 .. 2^(n-1)
+		step.Quo(step, big.NewFloat(2))
+		sum := evaluator.newFloat()
+		for k := int64(1); k <= points; k++ {
+			offset := evaluator.newFloat().Mul(step, evaluator.newFloat().SetInt64(2 * k - 1))
+			if value, err := evaluator.at(offset.Add(offset, a)); err != nil {
+				return nil, err
+			} else {
+				sum.Add(sum, value)
+			}
+		}
+		points *= 2
+		current := make([]*big.Float, row + 1)
+		current[0] = evaluator.newFloat().Quo(previous[0], big.NewFloat(2))
+		current[0].Add(current[0], sum.Mul(sum, step))
+		// R(n, m) = R(n, m - 1) + (R(n, m - 1) - R(n - 1, m - 1)) / (4^m - 1)
+		factor := evaluator.newFloat().SetInt64(1)
+		for column := 1; column <= row; column++ {
+			factor.Mul(factor, big.NewFloat(4))
+			correction := evaluator.newFloat().Sub(current[column - 1], previous[column - 1])
+			correction.Quo(correction, evaluator.newFloat().Sub(factor, big.NewFloat(1)))
+			current[column] = evaluator.newFloat().Add(current[column - 1], correction)
+		}
+		difference := evaluator.newFloat().Sub(current[row], previous[row - 1])
+		if row > 1 && difference.Abs(difference).Cmp(epsilon) <= 0 {
+			return current[row], nil
+		}
+		if uint64(points) > uint64(evaluator.remaining) {
+			return nil, integrationErrors.ErrEvaluationsExhausted
+		}
+		previous = current
+	}
+}
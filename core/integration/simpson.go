@@ -0,0 +1,88 @@
+package integration
+
+import (
+	"math/big"
+	"github.com/universe-10th/calculus/sets"
+)
+
+
+// simpsonRule computes (b - a) / 6 * (f(a) + 4 * f(m) + f(b)).
+func (evaluator *evaluator) simpsonRule(a, b, fa, fm, fb *big.Float) *big.Float {
+	sum := evaluator.newFloat().Mul(fm, big.NewFloat(4))
+	sum.Add(sum, fa)
+	sum.Add(sum, fb)
+	width := evaluator.newFloat().Sub(b, a)
+	width.Quo(width, big.NewFloat(6))
+	return sum.Mul(sum, width)
+}
+
+
+func (evaluator *evaluator) middle(a, b *big.Float) *big.Float {
+	middle := evaluator.newFloat().Add(a, b)
+	return middle.Quo(middle, big.NewFloat(2))
+}
+
+
+// adaptiveSimpson splits the [a, b] interval in two halves, and compares the
+// sum of both halves' estimates against the whole interval's one: if they are
+// close enough, the (Richardson-corrected) sum is returned. Otherwise, each
+// half is processed the same way, with half the epsilon.
+func (evaluator *evaluator) adaptiveSimpson(a, b, fa, fm, fb, whole, epsilon *big.Float) (*big.Float, error) {
+	m := evaluator.middle(a, b)
+	leftMiddle := evaluator.middle(a, m)
+	rightMiddle := evaluator.middle(m, b)
+	fLeftMiddle, err := evaluator.at(leftMiddle)
+	if err != nil {
+		return nil, err
+	}
+	fRightMiddle, err := evaluator.at(rightMiddle)
+	if err != nil {
+		return nil, err
+	}
+	left := evaluator.simpsonRule(a, m, fa, fLeftMiddle, fm)
+	right := evaluator.simpsonRule(m, b, fm, fRightMiddle, fb)
+	sum := evaluator.newFloat().Add(left, right)
+	delta := evaluator.newFloat().Sub(sum, whole)
+	if evaluator.newFloat().Abs(delta).Cmp(evaluator.newFloat().Mul(epsilon, big.NewFloat(15))) <= 0 {
+		return sum.Add(sum, delta.Quo(delta, big.NewFloat(15))), nil
+	}
+	halfEpsilon := evaluator.newFloat().Quo(epsilon, big.NewFloat(2))
+	if leftResult, err := evaluator.adaptiveSimpson(a, m, fa, fLeftMiddle, fm, left, halfEpsilon); err != nil {
+		return nil, err
+	} else if rightResult, err := evaluator.adaptiveSimpson(m, b, fm, fRightMiddle, fb, right, halfEpsilon); err != nil {
+		return nil, err
+	} else {
+		return leftResult.Add(leftResult, rightResult), nil
+	}
+}
+
+
+// AdaptiveSimpson estimates the definite integral of a function between two
+// bounds by the adaptive Simpson's method, splitting the interval where needed
+// until the estimated error is not greater than the epsilon. The function will
+// not be evaluated more than the given number of times: if the estimate is not
+// good enough by then, ErrEvaluationsExhausted is returned.
+func AdaptiveSimpson(function func(*big.Float) (sets.Number, error), lower, upper, epsilon *big.Float,
+	                 maxEvaluations uint32) (result *big.Float, exception error) {
+	defer recoverPanic(&result, &exception)
+	evaluator, err := newEvaluator(function, lower, upper, epsilon, maxEvaluations)
+	if err != nil {
+		return nil, err
+	}
+	a := evaluator.newFloat().Set(lower)
+	b := evaluator.newFloat().Set(upper)
+	m := evaluator.middle(a, b)
+	fa, err := evaluator.at(a)
+	if err != nil {
+		return nil, err
+	}
+	fm, err := evaluator.at(m)
+	if err != nil {
+		return nil, err
+	}
+	fb, err := evaluator.at(b)
+	if err != nil {
+		return nil, err
+	}
+	return evaluator.adaptiveSimpson(a, b, fa, fm, fb, evaluator.simpsonRule(a, b, fa, fm, fb), epsilon)
+}
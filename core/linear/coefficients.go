@@ -0,0 +1,76 @@
+package linear
+
+import (
+	"math/big"
+	"github.com/universe-10th/calculus/expressions"
+	"github.com/universe-10th/calculus/sets"
+	linearErrors "github.com/universe-10th/calculus/core/linear/errors"
+)
+
+
+// exactRat converts a number to a rational, exactly (floats are converted by
+// their exact binary value). Infinite floats are not supported.
+func exactRat(number sets.Number) (*big.Rat, bool) {
+	switch value := number.(type) {
+	case *big.Int:
+		return new(big.Rat).SetInt(value), true
+	case *big.Rat:
+		return new(big.Rat).Set(value), true
+	case *big.Float:
+		if value.IsInf() {
+			return nil, false
+		}
+		result, _ := value.Rat(nil)
+		return result, true
+	default:
+		return nil, false
+	}
+}
+
+
+// numericConstant evaluates an expression that must have no variables.
+func numericConstant(expression expressions.Expression) (*big.Rat, error) {
+	variables := expressions.Variables{}
+	expression.CollectVariables(variables)
+	if len(variables) != 0 {
+		return nil, linearErrors.ErrNotLinear
+	} else if value, err := expression.Evaluate(expressions.Arguments{}); err != nil {
+		return nil, err
+	} else if result, ok := exactRat(value); !ok {
+		return nil, linearErrors.ErrNotLinear
+	} else {
+		return result, nil
+	}
+}
+
+
+// LinearCoefficients tells whether an expression is linear on the given variables,
+// with numeric coefficients, and returns the coefficients (one per variable, in
+// the same order) and the constant term. This is detected symbolically: each
+// partial derivative must be a number (i.e. have no variables at all), and there
+// must be no other variables. Otherwise, ErrNotLinear is returned.
+func LinearCoefficients(
+	expression expressions.Expression, variables []expressions.Variable,
+) ([]*big.Rat, *big.Rat, error) {
+	coefficients := make([]*big.Rat, len(variables))
+	zeros := expressions.Arguments{}
+	for index, variable := range variables {
+		if derivative, err := expression.Derivative(variable); err != nil {
+			return nil, nil, err
+		} else if simplified, err := derivative.Simplify(); err != nil {
+			return nil, nil, err
+		} else if coefficient, err := numericConstant(simplified); err != nil {
+			return nil, nil, err
+		} else {
+			coefficients[index] = coefficient
+			zeros[variable] = big.NewInt(0)
+		}
+	}
+	if curried, err := expression.Curry(zeros); err != nil {
+		return nil, nil, err
+	} else if constant, err := numericConstant(curried); err != nil {
+		return nil, nil, err
+	} else {
+		return coefficients, constant, nil
+	}
+}
@@ -0,0 +1,7 @@
+package errors
+
+import "errors"
+
+
+var ErrNotLinear = errors.New("the equation is not linear, with numeric coefficients, on the given variables")
+var ErrNoVariables = errors.New("there are no variables to solve the system for")
@@ -0,0 +1,243 @@
+package linear
+
+import (
+	"math/big"
+	"github.com/universe-10th/calculus/expressions"
+	linearErrors "github.com/universe-10th/calculus/core/linear/errors"
+)
+
+
+// SolutionKind tells how many solutions a linear system has.
+type SolutionKind int
+
+
+const (
+	// The system has exactly one solution.
+	Unique SolutionKind = iota
+	// The system has infinitely many solutions, depending on free variables.
+	Infinite
+	// The system has no solution: its equations are inconsistent.
+	None
+)
+
+
+// String tells the kind of solution, in human words.
+func (kind SolutionKind) String() string {
+	switch kind {
+	case Unique:
+		return "unique"
+	case Infinite:
+		return "infinite"
+	case None:
+		return "none"
+	default:
+		return "unknown"
+	}
+}
+
+
+// Solution describes the solutions of a linear system. For unique and infinite
+// solutions, Parametric has an expression for each variable: in the infinite
+// case, they depend on the Free variables (which are given as themselves), and
+// in the unique case they are constants, also available in Values.
+type Solution struct {
+	Kind       SolutionKind
+	Values     map[expressions.Variable]*big.Rat
+	Parametric expressions.Substitutions
+	Free       []expressions.Variable
+}
+
+
+// integerRow scales a row of rationals by the least common multiple of their
+// denominators, so all of them become integers.
+func integerRow(row []*big.Rat) []*big.Int {
+	multiple := big.NewInt(1)
+	for _, value := range row {
+		denominator := value.Denom()
+		gcd := new(big.Int).GCD(nil, nil, multiple, denominator)
+		multiple.Mul(multiple, new(big.Int).Quo(denominator, gcd))
+	}
+	result := make([]*big.Int, len(row))
+	for index, value := range row {
+		scaled := new(big.Int).Mul(value.Num(), multiple)
+		result[index] = scaled.Quo(scaled, value.Denom())
+	}
+	return result
+}
+
+
+// eliminate brings the (augmented) matrix to row echelon form by the fraction-free
+// Gaussian elimination (Bareiss algorithm): all the divisions are exact, so the
+// entries stay integers of moderate size. It returns the pivot columns.
+func eliminate(matrix [][]*big.Int, columns int) []int {
+	pivots := []int{}
+	previous := big.NewInt(1)
+	row := 0
+	for column := 0; column < columns && row < len(matrix); column++ {
+		pivotRow := -1
+		for candidate := row; candidate < len(matrix); candidate++ {
+			if matrix[candidate][column].Sign() != 0 {
+				pivotRow = candidate
+				break
+			}
+		}
+		if pivotRow < 0 {
+			continue
+		}
+		matrix[row], matrix[pivotRow] = matrix[pivotRow], matrix[row]
+		pivot := matrix[row][column]
+		for below := row + 1; below < len(matrix); below++ {
+			factor := matrix[below][column]
+			for index := column + 1; index < len(matrix[below]); index++ {
+				// a(i, j) = (p * a(i, j) - a(i, c) * a(r, j)) / previous pivot
+				value := new(big.Int).Mul(pivot, matrix[below][index])
+				value.Sub(value, new(big.Int).Mul(factor, matrix[row][index]))
+				matrix[below][index] = value.Quo(value, previous)
+			}
+			matrix[below][column] = big.NewInt(0)
+		}
+		previous = pivot
+		pivots = append(pivots, column)
+		row++
+	}
+	return pivots
+}
+
+
+// An affine combination of the free variables: a constant, and a coefficient per free variable.
+type affine struct {
+	constant     *big.Rat
+	coefficients []*big.Rat
+}
+
+
+// expression converts an affine combination into an expression on the free variables.
+func (combination affine) expression(free []expressions.Variable) (expressions.Expression, error) {
+	terms := []expressions.Expression{}
+	for index, coefficient := range combination.coefficients {
+		if coefficient.Sign() != 0 {
+			terms = append(terms, expressions.Mul(expressions.Num(coefficient), free[index]))
+		}
+	}
+	terms = append(terms, expressions.Num(combination.constant))
+	return expressions.Add(terms...).Simplify()
+}
+
+
+// backSubstitute solves each pivot variable, from the last pivot row to the first
+// one, in terms of the free variables.
+func backSubstitute(matrix [][]*big.Int, columns int, pivots []int) ([]affine, []int) {
+	isPivot := make([]bool, columns)
+	for _, column := range pivots {
+		isPivot[column] = true
+	}
+	freeColumns := []int{}
+	solutions := make([]affine, columns)
+	for column := 0; column < columns; column++ {
+		if !isPivot[column] {
+			freeColumns = append(freeColumns, column)
+		}
+	}
+	// Free variables are given as themselves.
+	for position, column := range freeColumns {
+		coefficients := make([]*big.Rat, len(freeColumns))
+		for index := range coefficients {
+			coefficients[index] = new(big.Rat)
+		}
+		coefficients[position].SetInt64(1)
+		solutions[column] = affine{new(big.Rat), coefficients}
+	}
+	for row := len(pivots) - 1; row >= 0; row-- {
+		column := pivots[row]
+		// x(c) = (b - sum(a(j) * x(j), for j > c)) / a(c)
+		constant := new(big.Rat).SetInt(matrix[row][columns])
+		coefficients := make([]*big.Rat, len(freeColumns))
+		for index := range coefficients {
+			coefficients[index] = new(big.Rat)
+		}
+		for index := column + 1; index < columns; index++ {
+			if matrix[row][index].Sign() == 0 {
+				continue
+			}
+			factor := new(big.Rat).SetInt(matrix[row][index])
+			constant.Sub(constant, new(big.Rat).Mul(factor, solutions[index].constant))
+			for free, coefficient := range solutions[index].coefficients {
+				coefficients[free].Sub(coefficients[free], new(big.Rat).Mul(factor, coefficient))
+			}
+		}
+		pivot := new(big.Rat).SetInt(matrix[row][column])
+		constant.Quo(constant, pivot)
+		for _, coefficient := range coefficients {
+			coefficient.Quo(coefficient, pivot)
+		}
+		solutions[column] = affine{constant, coefficients}
+	}
+	return solutions, freeColumns
+}
+
+
+// SolveExpressions solves the linear system where each of the given expressions
+// equals zero, for the given variables. When no variables are given, the ones in
+// the expressions are used, in the order expressions.SortedVariables tells. The
+// expressions must be linear, with numeric coefficients, on the variables (see
+// LinearCoefficients). The system is solved exactly, by fraction-free Gaussian
+// elimination over integers (each equation is scaled by the denominators of its
+// coefficients), and the solution tells whether it is unique, infinite (where the
+// last variables, in the given order, are taken as the free ones) or none.
+func SolveExpressions(system []expressions.Expression, variables []expressions.Variable) (Solution, error) {
+	if variables == nil {
+		variables = expressions.SortedVariables(system...)
+	}
+	if len(variables) == 0 {
+		return Solution{}, linearErrors.ErrNoVariables
+	}
+	columns := len(variables)
+	matrix := make([][]*big.Int, len(system))
+	for index, expression := range system {
+		if coefficients, constant, err := LinearCoefficients(expression, variables); err != nil {
+			return Solution{}, err
+		} else {
+			// a1 * x1 + ... + an * xn + c = 0 => a1 * x1 + ... + an * xn = -c
+			matrix[index] = integerRow(append(coefficients, new(big.Rat).Neg(constant)))
+		}
+	}
+	pivots := eliminate(matrix, columns)
+	for row := len(pivots); row < len(matrix); row++ {
+		if matrix[row][columns].Sign() != 0 {
+			return Solution{Kind: None}, nil
+		}
+	}
+	solutions, freeColumns := backSubstitute(matrix, columns, pivots)
+	solution := Solution{Parametric: expressions.Substitutions{}}
+	for _, column := range freeColumns {
+		solution.Free = append(solution.Free, variables[column])
+	}
+	for column, variable := range variables {
+		if expression, err := solutions[column].expression(solution.Free); err != nil {
+			return Solution{}, err
+		} else {
+			solution.Parametric[variable] = expression
+		}
+	}
+	if len(freeColumns) == 0 {
+		solution.Kind = Unique
+		solution.Values = map[expressions.Variable]*big.Rat{}
+		for column, variable := range variables {
+			solution.Values[variable] = solutions[column].constant
+		}
+	} else {
+		solution.Kind = Infinite
+	}
+	return solution, nil
+}
+
+
+// Solve solves the linear system given by the equations, for the given variables.
+// See SolveExpressions for more details.
+func Solve(equations []expressions.Equation, variables []expressions.Variable) (Solution, error) {
+	system := make([]expressions.Expression, len(equations))
+	for index, equation := range equations {
+		system[index] = expressions.Sub(equation.Left, equation.Right)
+	}
+	return SolveExpressions(system, variables)
+}
@@ -1,6 +1,12 @@
 package support
 
-import "github.com/universe-10th/calculus/expressions"
+import (
+	"math/big"
+	"github.com/universe-10th/calculus/errors"
+	"github.com/universe-10th/calculus/expressions"
+	"github.com/universe-10th/calculus/ops"
+	"github.com/universe-10th/calculus/sets"
+)
 
 
 // Creates a polynomial expressions given an involved variable and their coefficients.
@@ -25,3 +31,262 @@ func PolynomialExpression(variable expressions.Variable, coefficients ...interfa
 		return addition
 	}
 }
+
+
+// Polynomial is a polynomial on a single variable, having exact (*big.Int,
+// *big.Rat) or approximate (*big.Float) coefficients. It is always kept
+// normalized: its main coefficient is never zero. This means the zero
+// polynomial has no coefficients at all, and its degree is -1. Operations
+// return new polynomials rather than modifying the current one.
+type Polynomial struct {
+	// Coefficients are stored from the constant one to the main one,
+	// so the index of each coefficient is the power it multiplies.
+	coefficients []sets.Number
+}
+
+
+// normalizedCoefficient converts integral rationals into integers.
+func normalizedCoefficient(coefficient sets.Number) sets.Number {
+	if value, ok := coefficient.(*big.Rat); ok && value.IsInt() {
+		return big.NewInt(0).Set(value.Num())
+	}
+	return coefficient
+}
+
+
+// newPolynomial takes the coefficients from the constant one to the main one,
+// and removes the (main) zero coefficients.
+func newPolynomial(coefficients []sets.Number) Polynomial {
+	length := len(coefficients)
+	for length > 0 && ops.IsZero(coefficients[length - 1]) {
+		length--
+	}
+	normalized := make([]sets.Number, length)
+	for index, coefficient := range coefficients[0:length] {
+		normalized[index] = normalizedCoefficient(coefficient)
+	}
+	return Polynomial{normalized}
+}
+
+
+// NewPolynomial creates a polynomial given its coefficients, which are specified
+// from main to constant (as in PolynomialExpression). Example: NewPolynomial(2, 3, 5)
+// stands for 2*X^2 + 3*X + 5. The coefficients must be primitive numbers or
+// *big.(Int, Rat, Float) numbers (which are copied).
+func NewPolynomial(coefficients ...interface{}) Polynomial {
+	length := len(coefficients)
+	reversed := make([]sets.Number, length)
+	for index, coefficient := range coefficients {
+		wrapped, _ := sets.Wrap(sets.Clone(coefficient))
+		reversed[length - 1 - index] = wrapped
+	}
+	return newPolynomial(reversed)
+}
+
+
+// PolynomialFromExpression recognizes a polynomial on the given variable out of
+// an expression (which is expanded beforehand). The coefficients must be numeric:
+// other variables, if any, must be curried beforehand. ErrNotPolynomial is returned
+// if the expression is not polynomial-shaped.
+func PolynomialFromExpression(expression expressions.Expression, variable expressions.Variable) (Polynomial, error) {
+	if coefficients, err := expressions.PolynomialCoefficients(expression, variable); err != nil {
+		return Polynomial{}, err
+	} else {
+		length := len(coefficients)
+		reversed := make([]sets.Number, length)
+		for index, coefficient := range coefficients {
+			reversed[length - 1 - index] = coefficient
+		}
+		return newPolynomial(reversed), nil
+	}
+}
+
+
+// Degree returns the degree of the polynomial, or -1 for the zero polynomial.
+func (polynomial Polynomial) Degree() int {
+	return len(polynomial.coefficients) - 1
+}
+
+
+// IsZero tells whether this is the zero polynomial.
+func (polynomial Polynomial) IsZero() bool {
+	return len(polynomial.coefficients) == 0
+}
+
+
+// Coefficient returns (a copy of) the coefficient multiplying the given power.
+// Powers beyond the degree (or negative ones) have a zero coefficient.
+func (polynomial Polynomial) Coefficient(power int) sets.Number {
+	if power < 0 || power >= len(polynomial.coefficients) {
+		return big.NewInt(0)
+	}
+	return sets.Clone(polynomial.coefficients[power])
+}
+
+
+// LeadingCoefficient returns (a copy of) the main coefficient, or zero
+// for the zero polynomial.
+func (polynomial Polynomial) LeadingCoefficient() sets.Number {
+	return polynomial.Coefficient(polynomial.Degree())
+}
+
+
+// Coefficients returns (copies of) the coefficients, from main to constant.
+func (polynomial Polynomial) Coefficients() []sets.Number {
+	length := len(polynomial.coefficients)
+	result := make([]sets.Number, length)
+	for index, coefficient := range polynomial.coefficients {
+		result[length - 1 - index] = sets.Clone(coefficient)
+	}
+	return result
+}
+
+
+// Add returns the sum of both polynomials.
+func (polynomial Polynomial) Add(other Polynomial) Polynomial {
+	length := len(polynomial.coefficients)
+	if len(other.coefficients) > length {
+		length = len(other.coefficients)
+	}
+	result := make([]sets.Number, length)
+	for index := range result {
+		result[index] = ops.Add(polynomial.Coefficient(index), other.Coefficient(index))
+	}
+	return newPolynomial(result)
+}
+
+
+// Sub returns the difference of both polynomials.
+func (polynomial Polynomial) Sub(other Polynomial) Polynomial {
+	return polynomial.Add(other.Scale(big.NewInt(-1)))
+}
+
+
+// Scale returns the polynomial multiplied by a number.
+func (polynomial Polynomial) Scale(factor sets.Number) Polynomial {
+	result := make([]sets.Number, len(polynomial.coefficients))
+	for index, coefficient := range polynomial.coefficients {
+		result[index] = ops.Mul(coefficient, factor)
+	}
+	return newPolynomial(result)
+}
+
+
+// Mul returns the product of both polynomials.
+func (polynomial Polynomial) Mul(other Polynomial) Polynomial {
+	if polynomial.IsZero() || other.IsZero() {
+		return Polynomial{}
+	}
+	result := make([]sets.Number, len(polynomial.coefficients) + len(other.coefficients) - 1)
+	for index := range result {
+		result[index] = big.NewInt(0)
+	}
+	for index, coefficient := range polynomial.coefficients {
+		for otherIndex, otherCoefficient := range other.coefficients {
+			result[index + otherIndex] = ops.Add(result[index + otherIndex], ops.Mul(coefficient, otherCoefficient))
+		}
+	}
+	return newPolynomial(result)
+}
+
+
+// DivMod performs the long division of this polynomial by the given divider,
+// returning the quotient and the remainder (whose degree is less than the one
+// of the divider). Dividing integer coefficients may yield rational ones. An
+// error is returned when dividing by the zero polynomial.
+func (polynomial Polynomial) DivMod(divider Polynomial) (Polynomial, Polynomial, error) {
+	if divider.IsZero() {
+		return Polynomial{}, Polynomial{}, errors.ErrDivisionByZero
+	}
+	dividerDegree := divider.Degree()
+	if polynomial.Degree() < dividerDegree {
+		return Polynomial{}, polynomial, nil
+	}
+	remainder := make([]sets.Number, len(polynomial.coefficients))
+	copy(remainder, polynomial.coefficients)
+	quotient := make([]sets.Number, polynomial.Degree() - dividerDegree + 1)
+	leading := divider.coefficients[dividerDegree]
+	for index := len(quotient) - 1; index >= 0; index-- {
+		factor := normalizedCoefficient(ops.Div(remainder[index + dividerDegree], leading))
+		quotient[index] = factor
+		for dividerIndex, coefficient := range divider.coefficients[0:dividerDegree] {
+			remainder[index + dividerIndex] = ops.Sub(remainder[index + dividerIndex], ops.Mul(factor, coefficient))
+		}
+		// The main term cancels by definition: approximate
+		// coefficients must not leave a residue there.
+		remainder[index + dividerDegree] = big.NewInt(0)
+	}
+	return newPolynomial(quotient), newPolynomial(remainder), nil
+}
+
+
+// GCD returns the monic greatest common divisor of both polynomials, by the
+// euclidean algorithm. The GCD of two zero polynomials is the zero polynomial.
+// This is intended for exact coefficients: approximate ones will seldom leave
+// exact zero remainders, and so the result will typically be 1.
+func (polynomial Polynomial) GCD(other Polynomial) Polynomial {
+	a, b := polynomial, other
+	for !b.IsZero() {
+		_, remainder, _ := a.DivMod(b)
+		a, b = b, remainder
+	}
+	if a.IsZero() {
+		return a
+	}
+	return a.Scale(ops.Inv(a.coefficients[a.Degree()]))
+}
+
+
+// Derivative returns the derivative of the polynomial.
+func (polynomial Polynomial) Derivative() Polynomial {
+	if polynomial.Degree() < 1 {
+		return Polynomial{}
+	}
+	result := make([]sets.Number, polynomial.Degree())
+	for index := range result {
+		result[index] = ops.Mul(polynomial.coefficients[index + 1], big.NewInt(int64(index + 1)))
+	}
+	return newPolynomial(result)
+}
+
+
+// Compose returns the composition of this polynomial with the given (inner)
+// one: the result of replacing the variable with the inner polynomial.
+func (polynomial Polynomial) Compose(inner Polynomial) Polynomial {
+	result := Polynomial{}
+	for index := polynomial.Degree(); index >= 0; index-- {
+		result = result.Mul(inner).Add(Polynomial{[]sets.Number{polynomial.coefficients[index]}})
+	}
+	return result
+}
+
+
+// Evaluate computes the value of the polynomial, by Horner's method, for the
+// given value (a primitive or *big.(Int, Rat, Float) number).
+func (polynomial Polynomial) Evaluate(value interface{}) sets.Number {
+	wrapped, _ := sets.Wrap(value)
+	var result sets.Number = big.NewInt(0)
+	for index := polynomial.Degree(); index >= 0; index-- {
+		result = ops.Add(ops.Mul(result, wrapped), polynomial.coefficients[index])
+	}
+	return normalizedCoefficient(result)
+}
+
+
+// Expression converts the polynomial to an expression on the given variable.
+func (polynomial Polynomial) Expression(variable expressions.Variable) expressions.Expression {
+	if polynomial.IsZero() {
+		return expressions.Num(0)
+	}
+	coefficients := make([]interface{}, len(polynomial.coefficients))
+	for index, coefficient := range polynomial.Coefficients() {
+		coefficients[index] = coefficient
+	}
+	return PolynomialExpression(variable, coefficients...)
+}
+
+
+// String represents the polynomial on the X variable.
+func (polynomial Polynomial) String() string {
+	return polynomial.Expression(expressions.X).String()
+}
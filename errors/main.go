@@ -12,7 +12,44 @@ var ErrInvertedVariableNotInDomain = errors.New("the expression does not depend
 // For derivative
 var ErrNotDerivableExpression = errors.New("this expression is not derivable")
 var ErrUndefinedOnInteger = errors.New("this expression is undefined on integer values")
+var ErrUndefinedOnZero = errors.New("this expression is undefined on zero")
 var ErrInfiniteCannotBeRounded = errors.New("infinite numbers cannot be rounded")
 // For model
 var ErrAmbiguousInputSpec = errors.New("cannot add a model flow because their input spec has conflicts with at least one already-added input spec")
-var ErrNoRegisteredFlowFowInput = errors.New("there is no registered model flow expecting the given arguments")
\ No newline at end of file
+var ErrNoRegisteredFlowFowInput = errors.New("there is no registered model flow expecting the given arguments")
+// For parsing
+var ErrWrongArgumentsCount = errors.New("wrong number of arguments given to the function")
+var ErrInvalidArgument = errors.New("an argument given to the function is not valid")
+var ErrInvalidRoundType = errors.New("the round type must be an integer constant among Ceil, Floor, Inward and Outward")
+// For encoding
+var ErrUnsupportedSchemaVersion = errors.New("the encoded expression uses an unsupported schema version")
+var ErrMalformedEncodedExpression = errors.New("the encoded expression is malformed")
+var ErrUnsupportedExpression = errors.New("the expression (or one of its nodes) is not supported by the encoder")
+var ErrUnregisteredFunction = errors.New("there is no function registered with the given name")
+var ErrUnregisteredGoalSeekingAlgorithm = errors.New("there is no goal-seeking algorithm factory registered with the given name")
+var ErrUnregisteredIntegrationAlgorithm = errors.New("there is no integration algorithm factory registered with the given name")
+// For polynomials
+var ErrNotPolynomial = errors.New("the expression is not a polynomial with numeric coefficients on the given variable")
+// For integration
+var ErrNotIntegrable = errors.New("this expression cannot be integrated symbolically")
+// For series
+var ErrNegativeSeriesOrder = errors.New("the order of a series expansion must not be negative")
+// For substitution
+var ErrCapturedVariable = errors.New("the substitution would make a bound variable (e.g. an integration or inverted one) capture a free one")
+// For rewriting
+var ErrUnboundWildcard = errors.New("the rewrite template uses a wildcard not present in the pattern")
+var ErrRewriteStepsExhausted = errors.New("the rewrite steps were exhausted before reaching a fixed point")
+// For equations
+var ErrVariableNotInEquation = errors.New("the variable to solve for is not present in the equation")
+var ErrNotIsolable = errors.New("the variable cannot be isolated symbolically in the equation")
+// For intervals
+var ErrEmptyInterval = errors.New("the lower bound of an interval must not be greater than its upper bound")
+var ErrUnsupportedIntervalExpression = errors.New("the expression cannot be evaluated over intervals")
+// For piecewise expressions
+var ErrNoMatchingBranch = errors.New("none of the conditions of the piecewise expression holds")
+// For inverse and reciprocal trigonometry
+var ErrArcOutOfDomain = errors.New("attempted to calculate the arc sine or arc cosine of a number out of [-1, 1]")
+var ErrAngleOfOrigin = errors.New("attempted to calculate the angle (two-argument arc tangent) of the origin")
+var ErrReciprocalTrigOfZero = errors.New("attempted to calculate a secant, cosecant or cotangent where the reciprocal function is 0")
+// For hyperbolic functions
+var ErrArcHyperbolicOutOfDomain = errors.New("attempted to calculate the inverse hyperbolic cosine of a number lower than 1, or the inverse hyperbolic tangent of a number out of (-1, 1)")
\ No newline at end of file
@@ -0,0 +1,310 @@
+package expressions
+
+import (
+	"fmt"
+	"math/big"
+	"github.com/universe-10th/calculus/errors"
+	"github.com/universe-10th/calculus/sets"
+	"github.com/universe-10th/calculus/ops"
+)
+
+
+// AbsExpr stands for the absolute value of an expression.
+type AbsExpr struct {
+	FunctionExpr
+	arg Expression
+}
+
+
+// Curry tries currying the underlying expression first, and then attempts simplifying.
+func (abs AbsExpr) Curry(args Arguments) (Expression, error) {
+	if curried, err := abs.arg.Curry(args); err != nil {
+		return nil, err
+	} else {
+		return Abs(curried).Simplify()
+	}
+}
+
+
+// Substitute replaces the variables in the underlying expression, and rebuilds the node.
+func (abs AbsExpr) Substitute(substitutions Substitutions) (Expression, error) {
+	if substituted, err := abs.arg.Substitute(substitutions); err != nil {
+		return nil, err
+	} else {
+		return Abs(substituted), nil
+	}
+}
+
+
+// Evaluate computes the inner expression and returns its absolute value (keeping its type).
+func (abs AbsExpr) Evaluate(args Arguments) (sets.Number, error) {
+	if result, err := abs.arg.Evaluate(args); err != nil {
+		return nil, err
+	} else {
+		return ops.Abs(result), nil
+	}
+}
+
+
+// Derivative is sign(x) * x' where x != 0, and undefined where x == 0: it is
+// guarded by a DefectiveOnZero factor, so it fails to evaluate there with the
+// ErrUndefinedOnZero error.
+func (abs AbsExpr) Derivative(wrt Variable) (Expression, error) {
+	if abs.arg.IsConstant(wrt) {
+		return Num(0), nil
+	} else if derivative, err := abs.arg.Derivative(wrt); err != nil {
+		return nil, err
+	} else {
+		return Mul(DefectiveOnZero{abs.arg, big.NewInt(1)}, Sign(abs.arg), derivative).Simplify()
+	}
+}
+
+
+// CollectVariables digs into the inner expression.
+func (abs AbsExpr) CollectVariables(variables Variables) {
+	abs.arg.CollectVariables(variables)
+}
+
+
+// IsConstant tells whether the inner expression is constant with respect to the given variable.
+func (abs AbsExpr) IsConstant(wrt Variable) bool {
+	return abs.arg.IsConstant(wrt)
+}
+
+
+// Simplify simplifies the inner expression and, if constant, returns its absolute value.
+// Also, |-x| and ||x|| become |x|.
+func (abs AbsExpr) Simplify() (Expression, error) {
+	if simplified, err := abs.arg.Simplify(); err != nil {
+		return nil, err
+	} else if num, ok := simplified.(Constant); ok {
+		return Constant{ops.Abs(num.number)}, nil
+	} else if negated, ok := simplified.(NegatedExpr); ok {
+		return Abs(negated.arg), nil
+	} else if _, ok := simplified.(AbsExpr); ok {
+		return simplified, nil
+	} else {
+		return Abs(simplified), nil
+	}
+}
+
+
+// Arguments builds a list consisting on the single argument.
+func (abs AbsExpr) Arguments() []Expression {
+	return []Expression{abs.arg}
+}
+
+
+// String represents the absolute value as a function, like abs(X).
+func (abs AbsExpr) String() string {
+	return FunctionDisplay(abs)
+}
+
+
+// LaTeX renders the absolute value between bars.
+func (abs AbsExpr) LaTeX() string {
+	return `\left|` + LaTeX(abs.arg) + `\right|`
+}
+
+
+// Abs constructs an absolute value expression.
+func Abs(arg Expression) Expression {
+	return AbsExpr{FunctionExpr{"abs"}, arg}
+}
+
+
+// SignExpr stands for the sign of an expression: -1, 0 or 1.
+type SignExpr struct {
+	FunctionExpr
+	arg Expression
+}
+
+
+// Curry tries currying the underlying expression first, and then attempts simplifying.
+func (sign SignExpr) Curry(args Arguments) (Expression, error) {
+	if curried, err := sign.arg.Curry(args); err != nil {
+		return nil, err
+	} else {
+		return Sign(curried).Simplify()
+	}
+}
+
+
+// Substitute replaces the variables in the underlying expression, and rebuilds the node.
+func (sign SignExpr) Substitute(substitutions Substitutions) (Expression, error) {
+	if substituted, err := sign.arg.Substitute(substitutions); err != nil {
+		return nil, err
+	} else {
+		return Sign(substituted), nil
+	}
+}
+
+
+// Evaluate computes the inner expression and returns its sign, as an integer.
+func (sign SignExpr) Evaluate(args Arguments) (sets.Number, error) {
+	if result, err := sign.arg.Evaluate(args); err != nil {
+		return nil, err
+	} else {
+		return ops.Sign(result), nil
+	}
+}
+
+
+// Derivative is 0 where x != 0, and undefined where x == 0 (the sign jumps
+// there): it is a DefectiveOnZero expression, failing with ErrUndefinedOnZero.
+func (sign SignExpr) Derivative(wrt Variable) (Expression, error) {
+	if sign.arg.IsConstant(wrt) {
+		return Num(0), nil
+	}
+	return DefectiveOnZero{sign.arg, big.NewInt(0)}, nil
+}
+
+
+// CollectVariables digs into the inner expression.
+func (sign SignExpr) CollectVariables(variables Variables) {
+	sign.arg.CollectVariables(variables)
+}
+
+
+// IsConstant tells whether the inner expression is constant with respect to the given variable.
+func (sign SignExpr) IsConstant(wrt Variable) bool {
+	return sign.arg.IsConstant(wrt)
+}
+
+
+// Simplify simplifies the inner expression and, if constant, returns its sign.
+// Also, sign(-x) becomes -sign(x), and sign(sign(x)) becomes sign(x).
+func (sign SignExpr) Simplify() (Expression, error) {
+	if simplified, err := sign.arg.Simplify(); err != nil {
+		return nil, err
+	} else if num, ok := simplified.(Constant); ok {
+		return Constant{ops.Sign(num.number)}, nil
+	} else if negated, ok := simplified.(NegatedExpr); ok {
+		return Negated(Sign(negated.arg)), nil
+	} else if _, ok := simplified.(SignExpr); ok {
+		return simplified, nil
+	} else {
+		return Sign(simplified), nil
+	}
+}
+
+
+// Arguments builds a list consisting on the single argument.
+func (sign SignExpr) Arguments() []Expression {
+	return []Expression{sign.arg}
+}
+
+
+// String represents the sign as a function, like sign(X).
+func (sign SignExpr) String() string {
+	return FunctionDisplay(sign)
+}
+
+
+// Sign constructs a sign expression.
+func Sign(arg Expression) Expression {
+	return SignExpr{FunctionExpr{"sign"}, arg}
+}
+
+
+// This expression evaluates to a constant if the underlying (bypassed) expression
+// is not zero, and returns an error otherwise. Like DefectiveOnInt, it is used
+// in derivatives: the ones of absolute values and signs are undefined at zero.
+type DefectiveOnZero struct {
+	bypassed Expression
+	result sets.Number
+}
+
+
+func (defectiveOnZero DefectiveOnZero) Curry(arguments Arguments) (Expression, error) {
+	if curried, err := defectiveOnZero.bypassed.Curry(arguments); err != nil {
+		return nil, err
+	} else {
+		return DefectiveOnZero{curried, defectiveOnZero.result}.Simplify()
+	}
+}
+
+
+func (defectiveOnZero DefectiveOnZero) Substitute(substitutions Substitutions) (Expression, error) {
+	if substituted, err := defectiveOnZero.bypassed.Substitute(substitutions); err != nil {
+		return nil, err
+	} else {
+		return DefectiveOnZero{substituted, defectiveOnZero.result}, nil
+	}
+}
+
+
+func (defectiveOnZero DefectiveOnZero) CollectVariables(variables Variables) {
+	defectiveOnZero.bypassed.CollectVariables(variables)
+}
+
+
+func (defectiveOnZero DefectiveOnZero) IsConstant(wrt Variable) bool {
+	return defectiveOnZero.bypassed.IsConstant(wrt)
+}
+
+
+func (defectiveOnZero DefectiveOnZero) Evaluate(args Arguments) (sets.Number, error) {
+	if evaluated, err := defectiveOnZero.bypassed.Evaluate(args); err != nil {
+		return nil, err
+	} else if ops.IsZero(evaluated) {
+		return nil, errors.ErrUndefinedOnZero
+	} else {
+		return defectiveOnZero.result, nil
+	}
+}
+
+
+func (defectiveOnZero DefectiveOnZero) Derivative(wrt Variable) (Expression, error) {
+	// Since defective-on-zero returns always a constant,
+	// or raises an error, depending on the underlying expression,
+	// its derivative will always be 0 (or undefined on zero).
+	return DefectiveOnZero{
+		defectiveOnZero.bypassed,
+		big.NewInt(0),
+	}, nil
+}
+
+
+func (defectiveOnZero DefectiveOnZero) Simplify() (Expression, error) {
+	if simplified, err := defectiveOnZero.bypassed.Simplify(); err != nil {
+		return nil, err
+	} else if num, ok := simplified.(Constant); ok {
+		if result, err := (DefectiveOnZero{num, defectiveOnZero.result}).Evaluate(Arguments{}); err != nil {
+			return nil, err
+		} else {
+			return Constant{result}, nil
+		}
+	} else {
+		return DefectiveOnZero{simplified, defectiveOnZero.result}, nil
+	}
+}
+
+
+func (defectiveOnZero DefectiveOnZero) String() string {
+	return fmt.Sprintf("DOZ(%s, %s)", defectiveOnZero.bypassed, defectiveOnZero.result)
+}
+
+
+func (defectiveOnZero DefectiveOnZero) IsSelfContained() bool {
+	return true
+}
+
+
+func buildDefectiveOnZero(arguments ...Expression) (Expression, error) {
+	if len(arguments) != 2 {
+		return nil, errors.ErrWrongArgumentsCount
+	}
+	if constant, ok := arguments[1].(Constant); !ok {
+		return nil, errors.ErrInvalidArgument
+	} else {
+		return DefectiveOnZero{arguments[0], constant.number}, nil
+	}
+}
+
+
+func init() {
+	RegisterFunction("abs", unary(Abs))
+	RegisterFunction("sign", unary(Sign))
+	RegisterFunction("doz", buildDefectiveOnZero)
+}
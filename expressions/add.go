@@ -30,6 +30,21 @@ func (add AddExpr) Curry(args Arguments) (Expression, error) {
 }
 
 
+// Substitute replaces the variables in each term independently, and then generates a new node.
+// Unlike Curry, it does not attempt simplifying the result.
+func (add AddExpr) Substitute(substitutions Substitutions) (Expression, error) {
+	newTerms := make([]Expression, len(add.terms))
+	for index, value := range add.terms {
+		if substituted, err := value.Substitute(substitutions); err != nil {
+			return nil, err
+		} else {
+			newTerms[index] = substituted
+		}
+	}
+	return Add(newTerms...), nil
+}
+
+
 // Evaluate computes the added evaluted values of the addition terms (recursively).
 func (add AddExpr) Evaluate(args Arguments) (sets.Number, error) {
 	terms := make([]sets.Number, len(add.terms))
@@ -77,37 +92,126 @@ func (add AddExpr) IsConstant(wrt Variable) bool {
 }
 
 
-// Simplify compresses all the constant terms into one single constant term.
+// splitCoefficient splits a term into its numeric coefficient and the rest
+// of the term (nil if the term is a constant). E.g. -3 * X * Y is split into
+// -3 and X * Y.
+func splitCoefficient(term Expression) (sets.Number, Expression) {
+	switch node := term.(type) {
+	case Constant:
+		return node.number, nil
+	case NegatedExpr:
+		coefficient, rest := splitCoefficient(node.arg)
+		return ops.Neg(coefficient), rest
+	case MulExpr:
+		coefficients := []sets.Number{}
+		rest := []Expression{}
+		for _, factor := range node.factors {
+			if constant, ok := factor.(Constant); ok {
+				coefficients = append(coefficients, constant.number)
+			} else {
+				rest = append(rest, factor)
+			}
+		}
+		if len(coefficients) == 0 {
+			return ops.One(sets.N), term
+		}
+		coefficient := ops.Mul(coefficients...)
+		switch len(rest) {
+		case 0:
+			return coefficient, nil
+		case 1:
+			return coefficient, rest[0]
+		default:
+			return coefficient, Mul(rest...)
+		}
+	default:
+		return ops.One(sets.N), term
+	}
+}
+
+
+// A like term is the rest of a term, and all the coefficients it was found with.
+type likeTerm struct {
+	rest         Expression
+	coefficients []sets.Number
+}
+
+
+// Simplify compresses all the constant terms into one single constant term, and
+// combines like terms by adding their numeric coefficients: X + 2 * X becomes
+// 3 * X, and X - X is dropped. The first occurrence of a term tells the place
+// the combined term will have, while the constant term is placed last.
 // The result is returned as a new expressions rather than modifying the current one.
 func (add AddExpr) Simplify() (Expression, error) {
-	simplifiedTerms := []sets.Number{}
-	nonSimplifiedTerms := []Expression{}
-
-	for _, term := range add.terms {
+	simplifiedTerms := make([]Expression, len(add.terms))
+	for index, term := range add.terms {
 		if simplified, err := term.Simplify(); err != nil {
 			return nil, err
-		} else if num, ok := simplified.(Constant); ok {
-			simplifiedTerms = append(simplifiedTerms, num.number)
 		} else {
-			nonSimplifiedTerms = append(nonSimplifiedTerms, simplified)
+			simplifiedTerms[index] = simplified
+		}
+	}
+	return simplifiedSum(simplifiedTerms), nil
+}
+
+
+// simplifiedSum does the actual simplification of the addition, assuming
+// the given terms are already simplified.
+func simplifiedSum(terms []Expression) Expression {
+	simplifiedTerms := []sets.Number{}
+	likeTerms := []*likeTerm{}
+
+	for _, term := range flattenTerms(terms) {
+		coefficient, rest := splitCoefficient(term)
+		if rest == nil {
+			simplifiedTerms = append(simplifiedTerms, coefficient)
+			continue
+		}
+		found := false
+		for _, like := range likeTerms {
+			if equivalent(like.rest, rest) {
+				like.coefficients = append(like.coefficients, coefficient)
+				found = true
+				break
+			}
+		}
+		if !found {
+			likeTerms = append(likeTerms, &likeTerm{rest, []sets.Number{coefficient}})
+		}
+	}
+
+	nonSimplifiedTerms := []Expression{}
+	for _, like := range likeTerms {
+		coefficient := normalizedNumber(ops.Add(like.coefficients...))
+		if ops.IsZero(coefficient) {
+			continue
+		} else if ops.IsOne(coefficient) {
+			nonSimplifiedTerms = append(nonSimplifiedTerms, like.rest)
+		} else if ops.IsOne(ops.Neg(coefficient)) {
+			nonSimplifiedTerms = append(nonSimplifiedTerms, Negated(like.rest))
+		} else {
+			nonSimplifiedTerms = append(nonSimplifiedTerms, Mul(Constant{coefficient}, like.rest))
 		}
 	}
 
 	simplifiedSummary := ops.Add(simplifiedTerms...)
+	if simplifiedSummary != nil {
+		simplifiedSummary = normalizedNumber(simplifiedSummary)
+	}
 	if len(nonSimplifiedTerms) != 0 {
 		if simplifiedSummary != nil && !ops.IsZero(simplifiedSummary) {
 			nonSimplifiedTerms = append(nonSimplifiedTerms, Constant{simplifiedSummary})
 		}
 		if len(nonSimplifiedTerms) == 1 {
-			return nonSimplifiedTerms[0], nil
+			return nonSimplifiedTerms[0]
 		} else {
-			return Add(nonSimplifiedTerms...), nil
+			return Add(nonSimplifiedTerms...)
 		}
 	} else {
 		if simplifiedSummary != nil {
-			return Constant{simplifiedSummary}, nil
+			return Constant{simplifiedSummary}
 		} else {
-			return Constant{ops.Zero(sets.N0)}, nil
+			return Constant{ops.Zero(sets.N0)}
 		}
 	}
 }
@@ -178,5 +282,8 @@ func Add(terms ...Expression) Expression {
 
 // Sub constructs a new subtracting addition node given their terms.
 func Sub(minuend Expression, subtrahends ...Expression) Expression {
+	if len(subtrahends) == 1 {
+		return Add(minuend, Negated(subtrahends[0]))
+	}
 	return Add(minuend, Negated(Add(subtrahends...)))
 }
\ No newline at end of file
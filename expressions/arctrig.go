@@ -0,0 +1,345 @@
+package expressions
+
+import (
+	"math/big"
+	"github.com/universe-10th/calculus/sets"
+	"github.com/universe-10th/calculus/ops"
+	"github.com/universe-10th/calculus/errors"
+)
+
+
+// ArcSinExpr stands for an arc sine expression. Its values are in [-pi/2, pi/2].
+type ArcSinExpr struct {
+	TrigFunctionExpr
+}
+
+
+// ArcCosExpr stands for an arc cosine expression. Its values are in [0, pi].
+type ArcCosExpr struct {
+	TrigFunctionExpr
+}
+
+
+// ArcTanExpr stands for an arc tangent expression. Its values are in (-pi/2, pi/2).
+type ArcTanExpr struct {
+	TrigFunctionExpr
+}
+
+
+// unitComplement builds the sqrt(1 - x^2) expression, used in the derivatives of arcs.
+func unitComplement(arg Expression) Expression {
+	return Pow(Sub(Num(1), Pow(arg, Num(2))), Num(big.NewRat(1, 2)))
+}
+
+
+// Simplify attempts reducing an arc sine expression to a constant.
+// It is an error if the constant is not in [-1, 1].
+func (arcSin ArcSinExpr) Simplify() (Expression, error) {
+	if simplified, err := arcSin.arg.Simplify(); err != nil {
+		return nil, err
+	} else if num, ok := simplified.(Constant); ok {
+		if result, err := wrappedTrig(ops.ArcSin, num.number, errors.ErrArcOutOfDomain); err != nil {
+			return nil, err
+		} else {
+			return Constant{result}, nil
+		}
+	} else {
+		return ArcSin(simplified), nil
+	}
+}
+
+
+// Curry tries currying the underlying expression first, and then attempts simplifying.
+func (arcSin ArcSinExpr) Curry(args Arguments) (Expression, error) {
+	if curried, err := arcSin.arg.Curry(args); err != nil {
+		return nil, err
+	} else {
+		return ArcSin(curried).Simplify()
+	}
+}
+
+
+// Substitute replaces the variables in the underlying expression, and rebuilds the node.
+func (arcSin ArcSinExpr) Substitute(substitutions Substitutions) (Expression, error) {
+	if substituted, err := arcSin.arg.Substitute(substitutions); err != nil {
+		return nil, err
+	} else {
+		return ArcSin(substituted), nil
+	}
+}
+
+
+// Evaluate computes the arc sine over the evaluated value of the inner expression.
+// It is an error if the value is not in [-1, 1].
+func (arcSin ArcSinExpr) Evaluate(args Arguments) (sets.Number, error) {
+	if result, err := arcSin.arg.Evaluate(args); err == nil {
+		return wrappedTrig(ops.ArcSin, result, errors.ErrArcOutOfDomain)
+	} else {
+		return nil, err
+	}
+}
+
+
+// Derivative uses the arc sine rule (1/sqrt(1 - x^2)) and also applies the chain rule.
+func (arcSin ArcSinExpr) Derivative(wrt Variable) (Expression, error) {
+	if derivative, err := arcSin.arg.Derivative(wrt); err != nil {
+		return nil, err
+	} else {
+		return Div(derivative, unitComplement(arcSin.arg)).Simplify()
+	}
+}
+
+
+// Simplify attempts reducing an arc cosine expression to a constant.
+// It is an error if the constant is not in [-1, 1].
+func (arcCos ArcCosExpr) Simplify() (Expression, error) {
+	if simplified, err := arcCos.arg.Simplify(); err != nil {
+		return nil, err
+	} else if num, ok := simplified.(Constant); ok {
+		if result, err := wrappedTrig(ops.ArcCos, num.number, errors.ErrArcOutOfDomain); err != nil {
+			return nil, err
+		} else {
+			return Constant{result}, nil
+		}
+	} else {
+		return ArcCos(simplified), nil
+	}
+}
+
+
+// Curry tries currying the underlying expression first, and then attempts simplifying.
+func (arcCos ArcCosExpr) Curry(args Arguments) (Expression, error) {
+	if curried, err := arcCos.arg.Curry(args); err != nil {
+		return nil, err
+	} else {
+		return ArcCos(curried).Simplify()
+	}
+}
+
+
+// Substitute replaces the variables in the underlying expression, and rebuilds the node.
+func (arcCos ArcCosExpr) Substitute(substitutions Substitutions) (Expression, error) {
+	if substituted, err := arcCos.arg.Substitute(substitutions); err != nil {
+		return nil, err
+	} else {
+		return ArcCos(substituted), nil
+	}
+}
+
+
+// Evaluate computes the arc cosine over the evaluated value of the inner expression.
+// It is an error if the value is not in [-1, 1].
+func (arcCos ArcCosExpr) Evaluate(args Arguments) (sets.Number, error) {
+	if result, err := arcCos.arg.Evaluate(args); err == nil {
+		return wrappedTrig(ops.ArcCos, result, errors.ErrArcOutOfDomain)
+	} else {
+		return nil, err
+	}
+}
+
+
+// Derivative uses the arc cosine rule (-1/sqrt(1 - x^2)) and also applies the chain rule.
+func (arcCos ArcCosExpr) Derivative(wrt Variable) (Expression, error) {
+	if derivative, err := arcCos.arg.Derivative(wrt); err != nil {
+		return nil, err
+	} else {
+		return Negated(Div(derivative, unitComplement(arcCos.arg))).Simplify()
+	}
+}
+
+
+// Simplify attempts reducing an arc tangent expression to a constant.
+func (arcTan ArcTanExpr) Simplify() (Expression, error) {
+	if simplified, err := arcTan.arg.Simplify(); err != nil {
+		return nil, err
+	} else if num, ok := simplified.(Constant); ok {
+		return Constant{ops.ArcTan(num.number)}, nil
+	} else {
+		return ArcTan(simplified), nil
+	}
+}
+
+
+// Curry tries currying the underlying expression first, and then attempts simplifying.
+func (arcTan ArcTanExpr) Curry(args Arguments) (Expression, error) {
+	if curried, err := arcTan.arg.Curry(args); err != nil {
+		return nil, err
+	} else {
+		return ArcTan(curried).Simplify()
+	}
+}
+
+
+// Substitute replaces the variables in the underlying expression, and rebuilds the node.
+func (arcTan ArcTanExpr) Substitute(substitutions Substitutions) (Expression, error) {
+	if substituted, err := arcTan.arg.Substitute(substitutions); err != nil {
+		return nil, err
+	} else {
+		return ArcTan(substituted), nil
+	}
+}
+
+
+// Evaluate computes the arc tangent over the evaluated value of the inner expression.
+func (arcTan ArcTanExpr) Evaluate(args Arguments) (sets.Number, error) {
+	if result, err := arcTan.arg.Evaluate(args); err == nil {
+		return ops.ArcTan(result), nil
+	} else {
+		return nil, err
+	}
+}
+
+
+// Derivative uses the arc tangent rule (1/(1 + x^2)) and also applies the chain rule.
+func (arcTan ArcTanExpr) Derivative(wrt Variable) (Expression, error) {
+	if derivative, err := arcTan.arg.Derivative(wrt); err != nil {
+		return nil, err
+	} else {
+		return Div(derivative, Add(Num(1), Pow(arcTan.arg, Num(2)))).Simplify()
+	}
+}
+
+
+// ArcSin constructs an arc sine expression.
+func ArcSin(arg Expression) Expression {
+	return ArcSinExpr{TrigFunctionExpr{FunctionExpr{"arcsin"},arg}}
+}
+
+
+// ArcCos constructs an arc cosine expression.
+func ArcCos(arg Expression) Expression {
+	return ArcCosExpr{TrigFunctionExpr{FunctionExpr{"arccos"},arg}}
+}
+
+
+// ArcTan constructs an arc tangent expression.
+func ArcTan(arg Expression) Expression {
+	return ArcTanExpr{TrigFunctionExpr{FunctionExpr{"arctan"},arg}}
+}
+
+
+// ArcTan2Expr stands for the angle of the (x, y) point: a two-argument arc
+// tangent taking into account the quadrant. Its values are in (-pi, pi].
+type ArcTan2Expr struct {
+	FunctionExpr
+	y, x Expression
+}
+
+
+// Curry tries currying both arguments first, and then attempts simplifying.
+func (arcTan2 ArcTan2Expr) Curry(args Arguments) (Expression, error) {
+	if curriedY, err := arcTan2.y.Curry(args); err != nil {
+		return nil, err
+	} else if curriedX, err := arcTan2.x.Curry(args); err != nil {
+		return nil, err
+	} else {
+		return ArcTan2(curriedY, curriedX).Simplify()
+	}
+}
+
+
+// Substitute replaces the variables in both arguments, and rebuilds the node.
+func (arcTan2 ArcTan2Expr) Substitute(substitutions Substitutions) (Expression, error) {
+	if substitutedY, err := arcTan2.y.Substitute(substitutions); err != nil {
+		return nil, err
+	} else if substitutedX, err := arcTan2.x.Substitute(substitutions); err != nil {
+		return nil, err
+	} else {
+		return ArcTan2(substitutedY, substitutedX), nil
+	}
+}
+
+
+// wrappedArcTan2 computes the angle, converting the panic at the origin into an error.
+func (arcTan2 ArcTan2Expr) wrappedArcTan2(y, x sets.Number) (result sets.Number, err error) {
+	defer func(){
+		if r := recover(); r != nil {
+			result = nil
+			err = errors.ErrAngleOfOrigin
+		}
+	}()
+	result = ops.ArcTan2(y, x)
+	return
+}
+
+
+// Evaluate computes the angle of the evaluated (x, y) point.
+// It is an error if the point is the origin.
+func (arcTan2 ArcTan2Expr) Evaluate(args Arguments) (sets.Number, error) {
+	if y, err := arcTan2.y.Evaluate(args); err != nil {
+		return nil, err
+	} else if x, err := arcTan2.x.Evaluate(args); err != nil {
+		return nil, err
+	} else {
+		return arcTan2.wrappedArcTan2(y, x)
+	}
+}
+
+
+// Derivative uses the rule (x * y' - y * x') / (x^2 + y^2).
+func (arcTan2 ArcTan2Expr) Derivative(wrt Variable) (Expression, error) {
+	if derivativeY, err := arcTan2.y.Derivative(wrt); err != nil {
+		return nil, err
+	} else if derivativeX, err := arcTan2.x.Derivative(wrt); err != nil {
+		return nil, err
+	} else {
+		return Div(
+			Sub(Mul(arcTan2.x, derivativeY), Mul(arcTan2.y, derivativeX)),
+			Add(Pow(arcTan2.x, Num(2)), Pow(arcTan2.y, Num(2))),
+		).Simplify()
+	}
+}
+
+
+// CollectVariables digs into both arguments.
+func (arcTan2 ArcTan2Expr) CollectVariables(variables Variables) {
+	arcTan2.y.CollectVariables(variables)
+	arcTan2.x.CollectVariables(variables)
+}
+
+
+// IsConstant tells whether both arguments are constant with respect to the given variable.
+func (arcTan2 ArcTan2Expr) IsConstant(wrt Variable) bool {
+	return arcTan2.y.IsConstant(wrt) && arcTan2.x.IsConstant(wrt)
+}
+
+
+// Simplify simplifies both arguments and, if both are constant, computes the angle.
+// It is an error if the point is the origin.
+func (arcTan2 ArcTan2Expr) Simplify() (Expression, error) {
+	if y, err := arcTan2.y.Simplify(); err != nil {
+		return nil, err
+	} else if x, err := arcTan2.x.Simplify(); err != nil {
+		return nil, err
+	} else {
+		constantY, okY := y.(Constant)
+		constantX, okX := x.(Constant)
+		if okY && okX {
+			if result, err := arcTan2.wrappedArcTan2(constantY.number, constantX.number); err != nil {
+				return nil, err
+			} else {
+				return Constant{result}, nil
+			}
+		}
+		return ArcTan2(y, x), nil
+	}
+}
+
+
+// Arguments returns (y, x) as the arguments, in the usual order.
+func (arcTan2 ArcTan2Expr) Arguments() []Expression {
+	return []Expression{arcTan2.y, arcTan2.x}
+}
+
+
+// String represents the angle as a function, like arctan2(Y, X).
+func (arcTan2 ArcTan2Expr) String() string {
+	return FunctionDisplay(arcTan2)
+}
+
+
+// ArcTan2 constructs the angle of the (x, y) point. Arguments go in the usual
+// order: y first, and then x.
+func ArcTan2(y, x Expression) Expression {
+	return ArcTan2Expr{FunctionExpr{"arctan2"}, y, x}
+}
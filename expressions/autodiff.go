@@ -0,0 +1,466 @@
+package expressions
+
+import (
+	"math/big"
+	"github.com/universe-10th/calculus/errors"
+	"github.com/universe-10th/calculus/ops"
+	"github.com/universe-10th/calculus/sets"
+)
+
+
+// isElementary tells whether a node is a plain function of the values of its
+// inner expressions. These nodes are differentiated numerically (by the local
+// partial derivatives), while the others (goal-seeks, integrals and custom
+// functions) are differentiated symbolically.
+func isElementary(expression Expression) bool {
+	switch expression.(type) {
+	case AddExpr, MulExpr, NegatedExpr, InverseExpr, PowExpr, LnExpr, LogExpr, ExpExpr,
+		 SinExpr, CosExpr, TanExpr, SecExpr, CscExpr, CotExpr, ArcSinExpr, ArcCosExpr, ArcTanExpr, ArcTan2Expr,
+		 SinhExpr, CoshExpr, TanhExpr, ArcSinhExpr, ArcCoshExpr, ArcTanhExpr,
+		 FactorialExpr, Round, Frac, DefectiveOnInt, DefectiveOnZero, AbsExpr, SignExpr:
+		return true
+	default:
+		return false
+	}
+}
+
+
+// elementaryValue evaluates an elementary node, given the values of its inner
+// expressions. The node itself is evaluated, so the errors are the same.
+func elementaryValue(expression Expression, values []sets.Number) (sets.Number, error) {
+	constants := make([]Expression, len(values))
+	for index, value := range values {
+		constants[index] = Constant{value}
+	}
+	if node, err := withArguments(expression, constants); err != nil {
+		return nil, err
+	} else {
+		return node.Evaluate(Arguments{})
+	}
+}
+
+
+// localPartials computes the partial derivatives of an elementary node with
+// respect to each of its inner expressions, given their values (and the value
+// of the node). Only the needed ones (i.e. the ones of inner expressions that
+// are not constant) are computed: the others are left nil. The rules are the
+// same the symbolic derivatives follow, and so are the errors: factorials are
+// not derivable, roundings are not derivable on integer values, and absolute
+// values and signs are not derivable at 0.
+func localPartials(expression Expression, values []sets.Number, value sets.Number, needed []bool) ([]sets.Number, error) {
+	partials := make([]sets.Number, len(values))
+	switch node := expression.(type) {
+	case AddExpr:
+		for index := range values {
+			partials[index] = big.NewInt(1)
+		}
+	case MulExpr:
+		for index := range values {
+			if !needed[index] {
+				continue
+			}
+			others := []sets.Number{big.NewInt(1)}
+			for otherIndex, other := range values {
+				if otherIndex != index {
+					others = append(others, other)
+				}
+			}
+			partials[index] = ops.Mul(others...)
+		}
+	case NegatedExpr:
+		partials[0] = big.NewInt(-1)
+	case InverseExpr:
+		// d(1/x) = -1/x^2
+		partials[0] = ops.Neg(ops.Mul(value, value))
+	case PowExpr:
+		// d(b^e) = e * b^(e - 1) * db + b^e * ln(b) * de
+		if needed[0] {
+			if power, err := node.wrappedPow(values[0], ops.Sub(values[1], big.NewInt(1))); err != nil {
+				return nil, err
+			} else {
+				partials[0] = ops.Mul(values[1], power)
+			}
+		}
+		if needed[1] {
+			if logarithm, err := (LnExpr{}).wrappedLn(values[0]); err != nil {
+				return nil, err
+			} else {
+				partials[1] = ops.Mul(value, logarithm)
+			}
+		}
+	case LnExpr:
+		partials[0] = ops.Inv(values[0])
+	case LogExpr:
+		// log(b, p) = ln(p) / ln(b): d = dp / (p * ln(b)) - log(b, p) * db / (b * ln(b))
+		if logarithm, err := (LnExpr{}).wrappedLn(values[0]); err != nil {
+			return nil, err
+		} else {
+			partials[0] = ops.Neg(ops.Div(value, ops.Mul(values[0], logarithm)))
+			partials[1] = ops.Inv(ops.Mul(values[1], logarithm))
+		}
+	case ExpExpr:
+		partials[0] = value
+	case SinExpr:
+		partials[0] = ops.Cos(values[0])
+	case CosExpr:
+		partials[0] = ops.Neg(ops.Sin(values[0]))
+	case TanExpr:
+		cosine := ops.Cos(values[0])
+		partials[0] = ops.Inv(ops.Mul(cosine, cosine))
+	case SecExpr:
+		// d(sec(x)) = sec(x) * tan(x)
+		partials[0] = ops.Mul(value, ops.Tan(values[0]))
+	case CscExpr:
+		// d(csc(x)) = -csc(x) * cot(x)
+		partials[0] = ops.Neg(ops.Mul(value, ops.Cot(values[0])))
+	case CotExpr:
+		// d(cot(x)) = -csc(x)^2
+		cosecant := ops.Csc(values[0])
+		partials[0] = ops.Neg(ops.Mul(cosecant, cosecant))
+	case ArcSinExpr, ArcCosExpr:
+		// d(arcsin(x)) = 1 / sqrt(1 - x^2) = -d(arccos(x))
+		complement, err := (PowExpr{}).wrappedPow(ops.Sub(big.NewInt(1), ops.Mul(values[0], values[0])), big.NewRat(1, 2))
+		if err != nil {
+			return nil, err
+		}
+		partials[0] = ops.Inv(complement)
+		if _, ok := expression.(ArcCosExpr); ok {
+			partials[0] = ops.Neg(partials[0])
+		}
+	case ArcTanExpr:
+		// d(arctan(x)) = 1 / (1 + x^2)
+		partials[0] = ops.Inv(ops.Add(big.NewInt(1), ops.Mul(values[0], values[0])))
+	case ArcTan2Expr:
+		// d(arctan2(y, x)) = (x * dy - y * dx) / (x^2 + y^2)
+		norm := ops.Add(ops.Mul(values[0], values[0]), ops.Mul(values[1], values[1]))
+		partials[0] = ops.Div(values[1], norm)
+		partials[1] = ops.Neg(ops.Div(values[0], norm))
+	case SinhExpr:
+		partials[0] = ops.Cosh(values[0])
+	case CoshExpr:
+		partials[0] = ops.Sinh(values[0])
+	case TanhExpr:
+		// d(tanh(x)) = 1 - tanh(x)^2
+		partials[0] = ops.Sub(big.NewInt(1), ops.Mul(value, value))
+	case ArcSinhExpr, ArcCoshExpr:
+		// d(arcsinh(x)) = 1 / sqrt(x^2 + 1), d(arccosh(x)) = 1 / sqrt(x^2 - 1)
+		offset := big.NewInt(1)
+		if _, ok := expression.(ArcCoshExpr); ok {
+			offset = big.NewInt(-1)
+		}
+		if root, err := (PowExpr{}).wrappedPow(ops.Add(ops.Mul(values[0], values[0]), offset), big.NewRat(1, 2)); err != nil {
+			return nil, err
+		} else if ops.IsZero(root) {
+			return nil, errors.ErrDivisionByZero
+		} else {
+			partials[0] = ops.Inv(root)
+		}
+	case ArcTanhExpr:
+		// d(arctanh(x)) = 1 / (1 - x^2)
+		partials[0] = ops.Inv(ops.Sub(big.NewInt(1), ops.Mul(values[0], values[0])))
+	case FactorialExpr:
+		if needed[0] {
+			return nil, errors.ErrNotDerivableExpression
+		}
+	case Round:
+		if partial, err := (DefectiveOnInt{Constant{values[0]}, big.NewInt(0)}).Evaluate(Arguments{}); err != nil {
+			return nil, err
+		} else {
+			partials[0] = partial
+		}
+	case Frac:
+		if needed[0] {
+			if partial, err := (DefectiveOnInt{Constant{values[0]}, big.NewInt(1)}).Evaluate(Arguments{}); err != nil {
+				return nil, err
+			} else {
+				partials[0] = partial
+			}
+		}
+	case DefectiveOnInt, DefectiveOnZero:
+		partials[0] = big.NewInt(0)
+	case AbsExpr:
+		// d|x| = sign(x), undefined at 0
+		if needed[0] && ops.IsZero(values[0]) {
+			return nil, errors.ErrUndefinedOnZero
+		}
+		partials[0] = ops.Sign(values[0])
+	case SignExpr:
+		if needed[0] && ops.IsZero(values[0]) {
+			return nil, errors.ErrUndefinedOnZero
+		}
+		partials[0] = big.NewInt(0)
+	}
+	return partials, nil
+}
+
+
+// A dual number keeps a value and its derivatives with respect to several
+// variables. Derivatives known to be zero are kept as nil.
+type dual struct {
+	value       sets.Number
+	derivatives []sets.Number
+}
+
+
+// forward evaluates an expression on dual numbers.
+func forward(expression Expression, arguments Arguments, variables []Variable) (dual, error) {
+	derivatives := make([]sets.Number, len(variables))
+	switch node := expression.(type) {
+	case Constant:
+		return dual{node.number, derivatives}, nil
+	case Variable:
+		if value, ok := arguments[node]; !ok {
+			return dual{}, errors.ErrUndefinedValue
+		} else {
+			for index, variable := range variables {
+				if variable == node {
+					derivatives[index] = big.NewInt(1)
+				}
+			}
+			return dual{value, derivatives}, nil
+		}
+	}
+	if !isElementary(expression) {
+		// Goal-seeks, integrals and custom functions are evaluated as usual,
+		// and their derivatives are computed symbolically.
+		value, err := expression.Evaluate(arguments)
+		if err != nil {
+			return dual{}, err
+		}
+		for index, variable := range variables {
+			if expression.IsConstant(variable) {
+				continue
+			} else if derivative, err := expression.Derivative(variable); err != nil {
+				return dual{}, err
+			} else if derivativeValue, err := derivative.Evaluate(arguments); err != nil {
+				return dual{}, err
+			} else {
+				derivatives[index] = derivativeValue
+			}
+		}
+		return dual{value, derivatives}, nil
+	}
+	inner := nodeArguments(expression)
+	values := make([]sets.Number, len(inner))
+	innerDuals := make([]dual, len(inner))
+	needed := make([]bool, len(inner))
+	for index, argument := range inner {
+		if innerDual, err := forward(argument, arguments, variables); err != nil {
+			return dual{}, err
+		} else {
+			innerDuals[index] = innerDual
+			values[index] = innerDual.value
+			for _, derivative := range innerDual.derivatives {
+				if derivative != nil {
+					needed[index] = true
+				}
+			}
+		}
+	}
+	value, err := elementaryValue(expression, values)
+	if err != nil {
+		return dual{}, err
+	}
+	partials, err := localPartials(expression, values, value, needed)
+	if err != nil {
+		return dual{}, err
+	}
+	// Chain rule: df = sum(df/dx(i) * dx(i)).
+	for index := range variables {
+		for innerIndex, innerDual := range innerDuals {
+			if innerDual.derivatives[index] == nil {
+				continue
+			}
+			term := ops.Mul(partials[innerIndex], innerDual.derivatives[index])
+			if derivatives[index] == nil {
+				derivatives[index] = term
+			} else {
+				derivatives[index] = ops.Add(derivatives[index], term)
+			}
+		}
+	}
+	return dual{value, derivatives}, nil
+}
+
+
+// EvaluateDual evaluates an expression and its first derivatives with respect
+// to each of the given variables (in the same order), in one pass over the
+// expression and without building the derivative expressions (forward-mode
+// automatic differentiation). When no variables are given, the ones in the
+// expression are used, in the order SortedVariables tells. Goal-seeks, integrals
+// and custom functions are differentiated symbolically, and expressions that
+// are not derivable symbolically fail here with the same errors.
+func EvaluateDual(expression Expression, arguments Arguments, variables []Variable) (sets.Number, []sets.Number, error) {
+	if variables == nil {
+		variables = SortedVariables(expression)
+	}
+	result, err := forward(expression, arguments, variables)
+	if err != nil {
+		return nil, nil, err
+	}
+	for index, derivative := range result.derivatives {
+		if derivative == nil {
+			result.derivatives[index] = big.NewInt(0)
+		} else {
+			result.derivatives[index] = normalizedNumber(derivative)
+		}
+	}
+	return result.value, result.derivatives, nil
+}
+
+
+// A tape entry records a node evaluated in the forward pass: its value,
+// the entries of its inner expressions, and its local partial derivatives.
+type tapeEntry struct {
+	expression Expression
+	inputs     []int
+	value      sets.Number
+	partials   []sets.Number
+	// Whether the node depends on any variable. Entries not depending
+	// on variables need no adjoint.
+	dependent bool
+}
+
+
+// A tape records the nodes of an expression in evaluation order, so the
+// inner expressions always come before the nodes using them.
+type tape struct {
+	entries   []tapeEntry
+	arguments Arguments
+}
+
+
+// record evaluates an expression, recording it (and its inner expressions)
+// in the tape. It returns the index of the entry of the expression.
+func (tape *tape) record(expression Expression) (int, error) {
+	entry := tapeEntry{expression: expression}
+	switch node := expression.(type) {
+	case Constant:
+		entry.value = node.number
+	case Variable:
+		if value, ok := tape.arguments[node]; !ok {
+			return 0, errors.ErrUndefinedValue
+		} else {
+			entry.value = value
+			entry.dependent = true
+		}
+	default:
+		if !isElementary(expression) {
+			if value, err := expression.Evaluate(tape.arguments); err != nil {
+				return 0, err
+			} else {
+				variables := Variables{}
+				expression.CollectVariables(variables)
+				entry.value = value
+				entry.dependent = len(variables) != 0
+			}
+			break
+		}
+		inner := nodeArguments(expression)
+		values := make([]sets.Number, len(inner))
+		needed := make([]bool, len(inner))
+		for index, argument := range inner {
+			if input, err := tape.record(argument); err != nil {
+				return 0, err
+			} else {
+				entry.inputs = append(entry.inputs, input)
+				values[index] = tape.entries[input].value
+				needed[index] = tape.entries[input].dependent
+				entry.dependent = entry.dependent || needed[index]
+			}
+		}
+		if value, err := elementaryValue(expression, values); err != nil {
+			return 0, err
+		} else if partials, err := localPartials(expression, values, value, needed); err != nil {
+			return 0, err
+		} else {
+			entry.value = value
+			entry.partials = partials
+		}
+	}
+	tape.entries = append(tape.entries, entry)
+	return len(tape.entries) - 1, nil
+}
+
+
+// accumulate adds a term to an adjoint, which may be nil (zero).
+func accumulate(adjoint, term sets.Number) sets.Number {
+	if adjoint == nil {
+		return term
+	}
+	return ops.Add(adjoint, term)
+}
+
+
+// backward propagates the adjoints, from the last entry (the output) to the
+// first ones, and accumulates the adjoints of the variables.
+func (tape *tape) backward(gradient Arguments) error {
+	adjoints := make([]sets.Number, len(tape.entries))
+	adjoints[len(adjoints) - 1] = big.NewInt(1)
+	for index := len(tape.entries) - 1; index >= 0; index-- {
+		entry := tape.entries[index]
+		adjoint := adjoints[index]
+		if adjoint == nil || !entry.dependent {
+			continue
+		}
+		switch node := entry.expression.(type) {
+		case Variable:
+			gradient[node] = accumulate(gradient[node], adjoint)
+		default:
+			if isElementary(node) {
+				for inputIndex, input := range entry.inputs {
+					if partial := entry.partials[inputIndex]; partial != nil && tape.entries[input].dependent {
+						adjoints[input] = accumulate(adjoints[input], ops.Mul(adjoint, partial))
+					}
+				}
+				continue
+			}
+			// Goal-seeks, integrals and custom functions are differentiated
+			// symbolically, with respect to each of their variables.
+			variables := Variables{}
+			node.CollectVariables(variables)
+			for variable := range variables {
+				if node.IsConstant(variable) {
+					continue
+				} else if derivative, err := node.Derivative(variable); err != nil {
+					return err
+				} else if value, err := derivative.Evaluate(tape.arguments); err != nil {
+					return err
+				} else {
+					gradient[variable] = accumulate(gradient[variable], ops.Mul(adjoint, value))
+				}
+			}
+		}
+	}
+	return nil
+}
+
+
+// EvaluateGradient evaluates an expression and its first derivatives with
+// respect to all of its variables, in one forward pass recording the nodes in
+// a tape and one backward pass propagating the adjoints (reverse-mode automatic
+// differentiation). The derivatives are returned as arguments: a value for each
+// variable in the expression. Like in EvaluateDual, goal-seeks, integrals and
+// custom functions are differentiated symbolically, and expressions that are
+// not derivable symbolically fail here with the same errors.
+func EvaluateGradient(expression Expression, arguments Arguments) (sets.Number, Arguments, error) {
+	recorder := &tape{arguments: arguments}
+	if output, err := recorder.record(expression); err != nil {
+		return nil, nil, err
+	} else {
+		variables := Variables{}
+		expression.CollectVariables(variables)
+		gradient := Arguments{}
+		if err := recorder.backward(gradient); err != nil {
+			return nil, nil, err
+		}
+		for variable := range variables {
+			if derivative, ok := gradient[variable]; ok {
+				gradient[variable] = normalizedNumber(derivative)
+			} else {
+				gradient[variable] = big.NewInt(0)
+			}
+		}
+		return recorder.entries[output].value, gradient, nil
+	}
+}
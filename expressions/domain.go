@@ -0,0 +1,291 @@
+package expressions
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"github.com/universe-10th/calculus/errors"
+	"github.com/universe-10th/calculus/ops"
+	"github.com/universe-10th/calculus/sets"
+)
+
+
+// ConditionKind tells what a domain condition requires from the value of its expression.
+type ConditionKind int
+const (
+	// The value must be > 0 (e.g. logarithm arguments).
+	Positive ConditionKind = iota
+	// The value must be >= 0 (e.g. bases of powers with fractional exponents).
+	NonNegative
+	// The value must be != 0 (e.g. denominators).
+	NonZero
+	// The value must not be an integer (e.g. DOI arguments).
+	NonInteger
+	// The value must belong to N0 (e.g. factorial arguments).
+	Natural
+)
+
+
+// Condition is a requirement the value of an expression must satisfy so another
+// expression is defined, and the error the evaluation fails with otherwise.
+type Condition struct {
+	expression Expression
+	kind       ConditionKind
+	err        error
+}
+
+
+// Expression returns the expression the condition is about.
+func (condition Condition) Expression() Expression {
+	return condition.expression
+}
+
+
+// Kind returns what the condition requires from the expression's value.
+func (condition Condition) Kind() ConditionKind {
+	return condition.kind
+}
+
+
+// isInteger tells whether a number has an integer value, like DefectiveOnInt does.
+func isInteger(number sets.Number) bool {
+	switch value := number.(type) {
+	case *big.Int:
+		return true
+	case *big.Rat:
+		return value.IsInt()
+	case *big.Float:
+		return value.IsInt()
+	default:
+		return false
+	}
+}
+
+
+// holds tells whether the value satisfies the condition.
+func (condition Condition) holds(value sets.Number) bool {
+	switch condition.kind {
+	case Positive:
+		return ops.IsPositive(value)
+	case NonNegative:
+		return !ops.IsNegative(value)
+	case NonZero:
+		return !ops.IsZero(value)
+	case NonInteger:
+		return !isInteger(value)
+	case Natural:
+		return sets.BelongsTo(value, sets.N0)
+	default:
+		return false
+	}
+}
+
+
+// Check evaluates the condition's expression and tells whether the condition
+// holds: it returns nil if it does, or the error the evaluation of the original
+// expression would fail with if it does not.
+func (condition Condition) Check(arguments Arguments) error {
+	if value, err := condition.expression.Evaluate(arguments); err != nil {
+		return err
+	} else if !condition.holds(value) {
+		return condition.err
+	} else {
+		return nil
+	}
+}
+
+
+// String represents the condition, like X > 0 or X not in Z.
+func (condition Condition) String() string {
+	switch condition.kind {
+	case Positive:
+		return fmt.Sprintf("%s > 0", condition.expression)
+	case NonNegative:
+		return fmt.Sprintf("%s >= 0", condition.expression)
+	case NonZero:
+		return fmt.Sprintf("%s != 0", condition.expression)
+	case NonInteger:
+		return fmt.Sprintf("%s not in Z", condition.expression)
+	case Natural:
+		return fmt.Sprintf("%s in N0", condition.expression)
+	default:
+		return fmt.Sprintf("%s ?", condition.expression)
+	}
+}
+
+
+// Conditions is the list of conditions under which an expression is defined.
+// Inner conditions come first, so each condition can be evaluated once the
+// previous ones hold.
+type Conditions []Condition
+
+
+// Check tells whether all the conditions hold for the given arguments, returning
+// the error of the first condition not holding (or failing to be evaluated).
+func (conditions Conditions) Check(arguments Arguments) error {
+	for _, condition := range conditions {
+		if err := condition.Check(arguments); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+
+// String represents the conditions, joined by "and".
+func (conditions Conditions) String() string {
+	if len(conditions) == 0 {
+		return "always"
+	}
+	texts := make([]string, len(conditions))
+	for index, condition := range conditions {
+		texts[index] = condition.String()
+	}
+	return strings.Join(texts, " and ")
+}
+
+
+// add appends a condition over the given expression, unless an equal one is
+// already present. Conditions over constant expressions are checked right
+// away: they are dropped if they hold, and their error is returned otherwise
+// (the expression is not defined anywhere).
+func (conditions Conditions) add(expression Expression, kind ConditionKind, err error) (Conditions, error) {
+	simplified, simplificationErr := expression.Simplify()
+	if simplificationErr != nil {
+		return nil, simplificationErr
+	}
+	condition := Condition{simplified, kind, err}
+	if constant, ok := simplified.(Constant); ok {
+		if condition.holds(constant.number) {
+			return conditions, nil
+		}
+		return nil, err
+	}
+	for _, existing := range conditions {
+		if existing.kind == kind && Equal(existing.expression, simplified) {
+			return conditions, nil
+		}
+	}
+	return append(conditions, condition), nil
+}
+
+
+// powerConditions tells the condition the base of a power must satisfy, given
+// its exponent: any base is allowed for non-negative integer exponents, and
+// non-zero bases for negative integer ones. Positive fractional exponents
+// allow non-negative bases, and any other exponent requires a positive base.
+func powerConditions(conditions Conditions, pow PowExpr) (Conditions, error) {
+	if constant, ok := pow.exponent.(Constant); ok {
+		switch {
+		case isInteger(constant.number) && !ops.IsNegative(constant.number):
+			return conditions, nil
+		case isInteger(constant.number):
+			return conditions.add(pow.base, NonZero, errors.ErrInvalidPowerOperation)
+		case ops.IsPositive(constant.number):
+			return conditions.add(pow.base, NonNegative, errors.ErrInvalidPowerOperation)
+		}
+	}
+	return conditions.add(pow.base, Positive, errors.ErrInvalidPowerOperation)
+}
+
+
+// domain collects the conditions of the inner expressions and then the
+// conditions of the node itself.
+func domain(expression Expression, conditions Conditions) (Conditions, error) {
+	var bound *Variable
+	switch node := expression.(type) {
+	case GoalSeekExpr:
+		bound = &node.inverted
+	case IntegralExpr:
+		bound = &node.wrt
+	}
+	arguments := nodeArguments(expression)
+	if piecewise, ok := expression.(PiecewiseExpr); ok {
+		// Only the first condition is always evaluated: the other conditions
+		// and the values are only evaluated in the regions they apply to.
+		arguments = nil
+		if len(piecewise.branches) != 0 {
+			arguments = []Expression{piecewise.branches[0].Condition}
+		}
+	}
+	for _, argument := range arguments {
+		inner, err := domain(argument, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, condition := range inner {
+			// Conditions on bound variables cannot be required from the inputs.
+			if bound != nil && !condition.expression.IsConstant(*bound) {
+				continue
+			}
+			if conditions, err = conditions.add(condition.expression, condition.kind, condition.err); err != nil {
+				return nil, err
+			}
+		}
+	}
+	switch node := expression.(type) {
+	case InverseExpr:
+		return conditions.add(node.arg, NonZero, errors.ErrDivisionByZero)
+	case PowExpr:
+		return powerConditions(conditions, node)
+	case LnExpr:
+		return conditions.add(node.arg, Positive, errors.ErrLogarithmOfNegative)
+	case LogExpr:
+		var err error
+		if conditions, err = conditions.add(node.power, Positive, errors.ErrLogarithmOfNegative); err != nil {
+			return nil, err
+		} else if conditions, err = conditions.add(node.base, Positive, errors.ErrLogarithmOfNegative); err != nil {
+			return nil, err
+		}
+		return conditions.add(Sub(node.base, Num(1)), NonZero, errors.ErrDivisionByZero)
+	case TanExpr:
+		return conditions.add(Cos(node.arg), NonZero, errors.ErrTangentOfVertical)
+	case SecExpr:
+		return conditions.add(Cos(node.arg), NonZero, errors.ErrReciprocalTrigOfZero)
+	case CscExpr, CotExpr:
+		return conditions.add(Sin(nodeArguments(node)[0]), NonZero, errors.ErrReciprocalTrigOfZero)
+	case ArcSinExpr, ArcCosExpr:
+		return conditions.add(Sub(Num(1), Pow(nodeArguments(node)[0], Num(2))), NonNegative, errors.ErrArcOutOfDomain)
+	case ArcTan2Expr:
+		return conditions.add(Add(Pow(node.x, Num(2)), Pow(node.y, Num(2))), NonZero, errors.ErrAngleOfOrigin)
+	case ArcCoshExpr:
+		return conditions.add(Sub(node.arg, Num(1)), NonNegative, errors.ErrArcHyperbolicOutOfDomain)
+	case ArcTanhExpr:
+		return conditions.add(Sub(Num(1), Pow(node.arg, Num(2))), Positive, errors.ErrArcHyperbolicOutOfDomain)
+	case FactorialExpr:
+		return conditions.add(node.arg, Natural, errors.ErrInvalidFactorialArgument)
+	case DefectiveOnInt:
+		return conditions.add(node.bypassed, NonInteger, errors.ErrUndefinedOnInteger)
+	case DefectiveOnZero:
+		return conditions.add(node.bypassed, NonZero, errors.ErrUndefinedOnZero)
+	default:
+		return conditions, nil
+	}
+}
+
+
+// Domain analyzes an expression and returns the conditions under which it is
+// defined: positive arguments for logarithms, non-zero denominators (inverses),
+// non-zero cosines for tangents and secants, non-zero sines for cotangents and
+// cosecants, arguments in [-1, 1] for arc sines and arc cosines, points other
+// than the origin for two-argument arc tangents, arguments not lower than 1 for
+// inverse hyperbolic cosines, arguments in (-1, 1) for inverse hyperbolic
+// tangents, natural arguments for factorials,
+// non-integer arguments for DOI expressions, non-zero arguments for DOZ
+// expressions (e.g. in derivatives of absolute values), and appropriate bases
+// for powers (see below).
+// Arguments can be validated against the conditions before evaluating the
+// expression. Conditions over constant values are checked right away: if one
+// does not hold, its error is returned. Conditions inside goal-seeks and
+// integrals involving their bound variables are not included, since they do
+// not depend on the inputs only. Likewise, only the conditions of the first
+// condition of a piecewise expression are included, since the rest of its
+// conditions and values are not evaluated everywhere.
+//
+// Powers having a non-negative integer exponent are defined for any base, while
+// negative integer exponents require a non-zero base. Positive non-integer
+// constant exponents require a non-negative base, and any other exponent requires
+// a positive base (a conservative condition, if the exponent is not constant).
+func Domain(expression Expression) (Conditions, error) {
+	return domain(expression, nil)
+}
@@ -0,0 +1,226 @@
+package expressions
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"hash/fnv"
+	"github.com/universe-10th/calculus/sets"
+)
+
+
+// exactNumberText represents a number by its exact value, regardless of its type.
+// This means: 2, 2/1 and 2.0 are all represented as "2".
+func exactNumberText(number sets.Number) string {
+	switch value := number.(type) {
+	case *big.Int:
+		return value.String()
+	case *big.Rat:
+		return value.RatString()
+	case *big.Float:
+		if value.IsInf() {
+			return value.String()
+		}
+		exact, _ := value.Rat(nil)
+		return exact.RatString()
+	default:
+		return fmt.Sprintf("%v", value)
+	}
+}
+
+
+// nodeLabel describes a node without its inner expressions: two nodes
+// having the same label and equal arguments are structurally equal.
+func nodeLabel(expression Expression) string {
+	switch node := expression.(type) {
+	case Constant:
+		return "#" + exactNumberText(node.number)
+	case Variable:
+		return "$" + node.name
+	case Round:
+		return fmt.Sprintf("%T:%d", node, node.roundType)
+	case DefectiveOnInt:
+		return fmt.Sprintf("%T:%s", node, exactNumberText(node.result))
+	case DefectiveOnZero:
+		return fmt.Sprintf("%T:%s", node, exactNumberText(node.result))
+	case GoalSeekExpr:
+		// Unnamed algorithm factories cannot be told apart (see hasUnnamedFactory).
+		return fmt.Sprintf("%T:%s:%s", node, node.inverted.name, node.factoryName)
+	case IntegralExpr:
+		return fmt.Sprintf("%T:%s:%s", node, node.wrt.name, node.factoryName)
+	case AddExpr, MulExpr, NegatedExpr, InverseExpr, PowExpr, FactorialExpr, Frac:
+		return fmt.Sprintf("%T", node)
+	case Function:
+		return fmt.Sprintf("%T:%s", node, node.StandardName())
+	default:
+		// Unknown expressions can only be told apart by their representation.
+		return fmt.Sprintf("%T:%s", node, node)
+	}
+}
+
+
+// isCommutative tells whether the order of the node's arguments is irrelevant.
+func isCommutative(expression Expression) bool {
+	switch expression.(type) {
+	case AddExpr, MulExpr, ExtremumExpr:
+		return true
+	default:
+		return false
+	}
+}
+
+
+// hasUnnamedFactory tells whether the node is a goal-seek or an integral built
+// with an unnamed algorithm factory. Functions cannot be compared in Go, and all
+// the closures built by the same function share their code, so there is no way
+// to tell whether two of these nodes use the same algorithm (or parameters).
+func hasUnnamedFactory(expression Expression) bool {
+	switch node := expression.(type) {
+	case GoalSeekExpr:
+		return node.factoryName == ""
+	case IntegralExpr:
+		return node.factoryName == ""
+	default:
+		return false
+	}
+}
+
+
+// equivalent tells whether two expressions are structurally equal, regardless
+// the order of the terms (factors) in additions (multiplications).
+func equivalent(a, b Expression) bool {
+	if hasUnnamedFactory(a) || hasUnnamedFactory(b) || nodeLabel(a) != nodeLabel(b) {
+		return false
+	}
+	argumentsA := nodeArguments(a)
+	argumentsB := nodeArguments(b)
+	if len(argumentsA) != len(argumentsB) {
+		return false
+	}
+	if !isCommutative(a) {
+		for index, argument := range argumentsA {
+			if !equivalent(argument, argumentsB[index]) {
+				return false
+			}
+		}
+		return true
+	}
+	// Since this is an equivalence relation, the first
+	// matching argument can be greedily taken each time.
+	matched := make([]bool, len(argumentsB))
+	for _, argumentA := range argumentsA {
+		found := false
+		for index, argumentB := range argumentsB {
+			if !matched[index] && equivalent(argumentA, argumentB) {
+				matched[index] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+
+// Equal tells whether two expressions are structurally equal. The order of the
+// terms (factors) of additions (multiplications) is irrelevant, and constants
+// are compared by their exact value, regardless of their type (so 2, 2/1 and
+// 2.0 are equal). Goal-seeks and integrals built with unnamed algorithm factories
+// (i.e. by GoalSeek and Integral) are never equal to anything, not even to
+// themselves, since their factories cannot be compared: use NamedGoalSeek and
+// NamedIntegral to get comparable ones.
+func Equal(a, b Expression) bool {
+	return equivalent(a, b)
+}
+
+
+// Hash computes a hash of an expression, consistent with Equal: equal expressions
+// have the same hash. It does not depend on the order of terms (factors), and it
+// is stable across runs. The factories of goal-seeks and integrals built with
+// unnamed algorithm factories are not hashed (those nodes are never equal).
+func Hash(expression Expression) uint64 {
+	hash := fnv.New64a()
+	hash.Write([]byte(nodeLabel(expression)))
+	arguments := nodeArguments(expression)
+	hashes := make([]uint64, len(arguments))
+	for index, argument := range arguments {
+		hashes[index] = Hash(argument)
+	}
+	if isCommutative(expression) {
+		sort.Slice(hashes, func(i, j int) bool {
+			return hashes[i] < hashes[j]
+		})
+	}
+	buffer := make([]byte, 8)
+	for _, argumentHash := range hashes {
+		for index := range buffer {
+			buffer[index] = byte(argumentHash >> uint(8 * index))
+		}
+		hash.Write(buffer)
+	}
+	return hash.Sum64()
+}
+
+
+// canonicalKey describes an expression, in a way that equal canonical expressions
+// have the same key. It is used to sort the terms (factors) deterministically.
+func canonicalKey(expression Expression) string {
+	arguments := nodeArguments(expression)
+	if len(arguments) == 0 {
+		return nodeLabel(expression)
+	}
+	keys := make([]string, len(arguments))
+	for index, argument := range arguments {
+		keys[index] = canonicalKey(argument)
+	}
+	return nodeLabel(expression) + "(" + strings.Join(keys, ",") + ")"
+}
+
+
+// Canonicalize sorts, recursively, the terms of the additions and the factors of
+// the multiplications in a deterministic order: constants go last in additions
+// and first in multiplications (as Simplify leaves them), while other terms
+// (factors) are sorted by their structure. Equal expressions become identical
+// once canonicalized, except for their constants: those are left untouched, so
+// equal constants of different types (e.g. 2, 2/1 and 2.0) are still represented
+// differently by String(). No other simplification is done.
+func Canonicalize(expression Expression) (Expression, error) {
+	canonical, err := mapArguments(expression, Canonicalize)
+	if err != nil {
+		return nil, err
+	}
+	if !isCommutative(canonical) {
+		return canonical, nil
+	}
+	_, isAddition := canonical.(AddExpr)
+	arguments := nodeArguments(canonical)
+	keys := make([]string, len(arguments))
+	positions := make([]int, len(arguments))
+	for index, argument := range arguments {
+		keys[index] = canonicalKey(argument)
+		positions[index] = index
+	}
+	// Constants are placed last in additions, and first in multiplications.
+	rank := func(index int) int {
+		_, isConstant := arguments[index].(Constant)
+		if isConstant == isAddition {
+			return 1
+		}
+		return 0
+	}
+	sort.SliceStable(positions, func(i, j int) bool {
+		if rankI, rankJ := rank(positions[i]), rank(positions[j]); rankI != rankJ {
+			return rankI < rankJ
+		}
+		return keys[positions[i]] < keys[positions[j]]
+	})
+	sorted := make([]Expression, len(arguments))
+	for index, position := range positions {
+		sorted[index] = arguments[position]
+	}
+	return withArguments(canonical, sorted)
+}
@@ -0,0 +1,170 @@
+package expressions
+
+import (
+	"fmt"
+	"github.com/universe-10th/calculus/errors"
+)
+
+
+// Equation stands for the equality of two expressions.
+type Equation struct {
+	Left, Right Expression
+}
+
+
+// String represents the equation as both expressions around a == sign.
+func (equation Equation) String() string {
+	return fmt.Sprintf("%s == %s", equation.Left, equation.Right)
+}
+
+
+// occurrences counts how many times a variable appears in an expression. Nodes
+// binding the variable (e.g. integrals on it) or not telling their arguments
+// (e.g. custom expressions) count as many, since they cannot be inverted.
+func occurrences(expression Expression, wrt Variable) int {
+	if variable, ok := expression.(Variable); ok && variable == wrt {
+		return 1
+	} else if expression.IsConstant(wrt) {
+		return 0
+	}
+	switch expression.(type) {
+	case GoalSeekExpr, IntegralExpr:
+		return 2
+	}
+	arguments := nodeArguments(expression)
+	if len(arguments) == 0 {
+		return 2
+	}
+	count := 0
+	for _, argument := range arguments {
+		count += occurrences(argument, wrt)
+	}
+	return count
+}
+
+
+// isolate solves left == right for the variable, which appears only once in
+// the left side (and not in the right side), by inverting the left side's nodes
+// one by one, from the outer one to the variable.
+func isolate(left, right Expression, wrt Variable) (Expression, error) {
+	if variable, ok := left.(Variable); ok && variable == wrt {
+		return right, nil
+	}
+	switch node := left.(type) {
+	case AddExpr:
+		// a + f(X) + b = r => f(X) = r - a - b
+		others := []Expression{}
+		var inner Expression
+		for _, term := range node.terms {
+			if term.IsConstant(wrt) {
+				others = append(others, term)
+			} else {
+				inner = term
+			}
+		}
+		return isolate(inner, Sub(right, others...), wrt)
+	case MulExpr:
+		// a * f(X) * b = r => f(X) = r / (a * b)
+		others := []Expression{}
+		var inner Expression
+		for _, factor := range node.factors {
+			if factor.IsConstant(wrt) {
+				others = append(others, factor)
+			} else {
+				inner = factor
+			}
+		}
+		return isolate(inner, Div(right, others...), wrt)
+	case NegatedExpr:
+		return isolate(node.arg, Negated(right), wrt)
+	case InverseExpr:
+		return isolate(node.arg, Inverse(right), wrt)
+	case PowExpr:
+		if node.exponent.IsConstant(wrt) {
+			// f(X)^a = r => f(X) = r^(1/a)
+			return isolate(node.base, Pow(right, Inverse(node.exponent)), wrt)
+		} else {
+			// a^f(X) = r => f(X) = log(a, r)
+			return isolate(node.exponent, Log(node.base, right), wrt)
+		}
+	case ExpExpr:
+		return isolate(node.exponent, Ln(right), wrt)
+	case LnExpr:
+		return isolate(node.arg, Exp(right), wrt)
+	case LogExpr:
+		if node.base.IsConstant(wrt) {
+			// log(a, f(X)) = r => f(X) = a^r
+			return isolate(node.power, Pow(node.base, right), wrt)
+		} else {
+			// log(f(X), a) = r => f(X) = a^(1/r)
+			return isolate(node.base, Pow(node.power, Inverse(right)), wrt)
+		}
+	case SinExpr:
+		return isolate(node.arg, ArcSin(right), wrt)
+	case CosExpr:
+		return isolate(node.arg, ArcCos(right), wrt)
+	case TanExpr:
+		return isolate(node.arg, ArcTan(right), wrt)
+	case SecExpr:
+		return isolate(node.arg, ArcCos(Inverse(right)), wrt)
+	case CscExpr:
+		return isolate(node.arg, ArcSin(Inverse(right)), wrt)
+	case CotExpr:
+		return isolate(node.arg, ArcTan(Inverse(right)), wrt)
+	case ArcSinExpr:
+		return isolate(node.arg, Sin(right), wrt)
+	case ArcCosExpr:
+		return isolate(node.arg, Cos(right), wrt)
+	case ArcTanExpr:
+		return isolate(node.arg, Tan(right), wrt)
+	case SinhExpr:
+		return isolate(node.arg, ArcSinh(right), wrt)
+	case CoshExpr:
+		return isolate(node.arg, ArcCosh(right), wrt)
+	case TanhExpr:
+		return isolate(node.arg, ArcTanh(right), wrt)
+	case ArcSinhExpr:
+		return isolate(node.arg, Sinh(right), wrt)
+	case ArcCoshExpr:
+		return isolate(node.arg, Cosh(right), wrt)
+	case ArcTanhExpr:
+		return isolate(node.arg, Tanh(right), wrt)
+	default:
+		return nil, errors.ErrNotIsolable
+	}
+}
+
+
+// Solve finds the value of a variable satisfying the equation, as an expression
+// on the other variables. When the variable appears only once in the equation,
+// it is isolated symbolically by inverting additions, multiplications, powers,
+// exponentials, logarithms, negations, inverses and (inverse) trigonometric and
+// hyperbolic functions (e.g. B + X * A == Y becomes X == (Y - B) / A). Even
+// powers are inverted by the principal root, so only the non-negative solution
+// is found (X^2 == 4 gives X == 2). Likewise, trigonometric functions are inverted
+// by their principal arcs (sin(X) == 1 gives X == arcsin(1), only), and the
+// hyperbolic cosine by the non-negative inverse. The solution
+// holds only where the inverted operations are defined. When the variable cannot be
+// isolated, a goal-seek expression using the fallback factory (on Left - Right,
+// with a goal of 0) is returned instead, or ErrNotIsolable if there is no such
+// fallback factory.
+func Solve(equation Equation, wrt Variable, fallback GoalSeekingAlgorithmFactory) (Expression, error) {
+	left, right := equation.Left, equation.Right
+	leftCount := occurrences(left, wrt)
+	rightCount := occurrences(right, wrt)
+	if leftCount == 0 && rightCount == 0 {
+		return nil, errors.ErrVariableNotInEquation
+	}
+	if leftCount == 0 && rightCount == 1 {
+		left, right = right, left
+	}
+	if leftCount + rightCount == 1 {
+		if solution, err := isolate(left, right, wrt); err == nil {
+			return solution.Simplify()
+		}
+	}
+	if fallback == nil {
+		return nil, errors.ErrNotIsolable
+	}
+	return GoalSeek(Num(0), Sub(equation.Left, equation.Right), wrt, fallback), nil
+}
@@ -0,0 +1,209 @@
+package expressions
+
+import (
+	"math/big"
+	"github.com/universe-10th/calculus/sets"
+)
+
+
+// The largest exponent a power of an addition will be expanded for.
+// Larger powers are kept as they are, since their expansion is huge.
+const maxExpandedExponent = 1 << 10
+
+
+// The largest number of terms a power or a product may be expanded into.
+// Larger expansions are not done: the powers or products are kept, with
+// their inner expressions expanded on their own.
+const maxExpandedTerms = 1 << 10
+
+
+// expandableExponent tells the exponent of a power, if it is
+// a non-negative integer constant small enough to be expanded.
+func expandableExponent(exponent Expression) (int, bool) {
+	if constant, ok := exponent.(Constant); !ok || !sets.BelongsTo(constant.number, sets.N0) {
+		return 0, false
+	} else {
+		value := big.NewRat(0, 1)
+		switch number := constant.number.(type) {
+		case *big.Int:
+			value.SetInt(number)
+		case *big.Rat:
+			value.Set(number)
+		case *big.Float:
+			number.Rat(value)
+		}
+		if !value.Num().IsInt64() || value.Num().Int64() > maxExpandedExponent {
+			return 0, false
+		}
+		return int(value.Num().Int64()), true
+	}
+}
+
+
+// multinomialCoefficient computes n! / (k1! * k2! * ... * km!), where n is
+// the sum of all the k's, as a product of binomial coefficients.
+func multinomialCoefficient(counts []int) *big.Int {
+	result := big.NewInt(1)
+	remaining := 0
+	for _, count := range counts {
+		remaining += count
+	}
+	for _, count := range counts {
+		result.Mul(result, new(big.Int).Binomial(int64(remaining), int64(count)))
+		remaining -= count
+	}
+	return result
+}
+
+
+// multinomialTermsCount tells how many terms the expansion of an addition of
+// the given number of terms, raised to the given exponent, has: the number of
+// ways of choosing the k's adding the exponent, (n + m - 1)! / (n! * (m - 1)!).
+func multinomialTermsCount(terms, exponent int) *big.Int {
+	return new(big.Int).Binomial(int64(exponent + terms - 1), int64(terms - 1))
+}
+
+
+// multinomialTerms expands (t1 + t2 + ... + tm)^n by the multinomial theorem:
+// the sum, for all the k's adding n, of n!/(k1!...km!) * t1^k1 * ... * tm^km.
+// Each term is repeated as a factor instead of being raised, so the products
+// are flattened (and their coefficients merged) later, when simplifying.
+func multinomialTerms(terms []Expression, exponent int) []Expression {
+	result := []Expression{}
+	counts := make([]int, len(terms))
+	var distribute func(index, remaining int)
+	distribute = func(index, remaining int) {
+		if index == len(terms) - 1 {
+			counts[index] = remaining
+			factors := []Expression{Constant{multinomialCoefficient(counts)}}
+			for termIndex, count := range counts {
+				for times := 0; times < count; times++ {
+					factors = append(factors, terms[termIndex])
+				}
+			}
+			result = append(result, Mul(factors...))
+			return
+		}
+		for count := remaining; count >= 0; count-- {
+			counts[index] = count
+			distribute(index + 1, remaining - count)
+		}
+	}
+	distribute(0, exponent)
+	return result
+}
+
+
+// expandedTerms expands an expression into the (not yet simplified) terms it
+// adds. Inverses are not distributed: their arguments are expanded on their own.
+func expandedTerms(expression Expression) ([]Expression, error) {
+	switch node := expression.(type) {
+	case Constant, Variable:
+		return []Expression{node}, nil
+	case AddExpr:
+		result := []Expression{}
+		for _, term := range node.terms {
+			if terms, err := expandedTerms(term); err != nil {
+				return nil, err
+			} else {
+				result = append(result, terms...)
+			}
+		}
+		return result, nil
+	case NegatedExpr:
+		if terms, err := expandedTerms(node.arg); err != nil {
+			return nil, err
+		} else {
+			for index, term := range terms {
+				terms[index] = Negated(term)
+			}
+			return terms, nil
+		}
+	case MulExpr:
+		result := []Expression{Num(1)}
+		for _, factor := range node.factors {
+			if terms, err := expandedTerms(factor); err != nil {
+				return nil, err
+			} else if len(result) * len(terms) > maxExpandedTerms {
+				if expanded, err := mapArguments(node, Expand); err != nil {
+					return nil, err
+				} else {
+					return []Expression{expanded}, nil
+				}
+			} else {
+				distributed := make([]Expression, 0, len(result) * len(terms))
+				for _, partial := range result {
+					for _, term := range terms {
+						distributed = append(distributed, Mul(partial, term))
+					}
+				}
+				// Like terms are merged right away, so the expansion
+				// only grows as much as the distinct monomials do.
+				if result, err = mergedTerms(distributed); err != nil {
+					return nil, err
+				}
+			}
+		}
+		return result, nil
+	case PowExpr:
+		exponent, err := Expand(node.exponent)
+		if err != nil {
+			return nil, err
+		}
+		if count, ok := expandableExponent(exponent); ok {
+			if terms, err := expandedTerms(node.base); err != nil {
+				return nil, err
+			} else if multinomialTermsCount(len(terms), count).Cmp(big.NewInt(maxExpandedTerms)) <= 0 {
+				return multinomialTerms(terms, count), nil
+			}
+		}
+		if base, err := Expand(node.base); err != nil {
+			return nil, err
+		} else {
+			return []Expression{Pow(base, exponent)}, nil
+		}
+	default:
+		if expanded, err := mapArguments(expression, Expand); err != nil {
+			return nil, err
+		} else {
+			return []Expression{expanded}, nil
+		}
+	}
+}
+
+
+// mergedTerms simplifies the terms of an addition, and combines the like ones.
+func mergedTerms(terms []Expression) ([]Expression, error) {
+	simplifiedTerms := make([]Expression, len(terms))
+	for index, term := range terms {
+		if simplified, err := term.Simplify(); err != nil {
+			return nil, err
+		} else {
+			simplifiedTerms[index] = simplified
+		}
+	}
+	merged := simplifiedSum(simplifiedTerms)
+	if sum, ok := merged.(AddExpr); ok {
+		return sum.terms, nil
+	} else {
+		return []Expression{merged}, nil
+	}
+}
+
+
+// Expand distributes the products over the additions, and expands the powers
+// of additions having non-negative integer exponents by the multinomial theorem
+// (e.g. (X + Y)^2 becomes X^2 + 2 * X * Y + Y^2). The arguments of any other node
+// (functions, inverses, non-integer powers) are expanded on their own. The result
+// is a flat, simplified, addition of monomials (or a single monomial). Powers and
+// products whose expansion would have more than 1024 terms are not distributed,
+// but their inner expressions are expanded on their own.
+func Expand(expression Expression) (Expression, error) {
+	if terms, err := expandedTerms(expression); err != nil {
+		return nil, err
+	} else if merged, err := mergedTerms(terms); err != nil {
+		return nil, err
+	} else {
+		return simplifiedSum(merged), nil
+	}
+}
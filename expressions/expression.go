@@ -5,16 +5,14 @@ package expressions
 
 import (
 	"fmt"
+	"strings"
 	"github.com/universe-10th/calculus/sets"
 	"math/big"
 	"github.com/universe-10th/calculus/errors"
+	"github.com/universe-10th/calculus/ops"
 )
 
 
-var zero = big.NewFloat(0)
-var one  = big.NewFloat(1)
-
-
 // Constant stands for a simple number node, e.g. 3, 4/5, or 3.1415926535.
 type Constant struct {
 	number sets.Number
@@ -35,6 +33,10 @@ type Variables map[Variable]bool
 type Arguments map[Variable]sets.Number
 
 
+// Substitutions are a map of variables to the expressions replacing them.
+type Substitutions map[Variable]Expression
+
+
 // Expression is the interface behind each node.
 type Expression interface {
 	// CollectVariables enumerates all the variables involved recursively in the node into the given set.
@@ -44,6 +46,12 @@ type Expression interface {
 	// In the bound cases, passing no relevant argument will return a cloned expression, and
 	// passing all the relevant arguments will return a constant, simplified, expression.
 	Curry(arguments Arguments) (Expression, error)
+	// Substitute generates a new expression by replacing the variables with the given expressions.
+	// E.g. Mul(X, Y).Substitute(Substitutions{Y: Add(X, Num(1))}) would yield X * (X + 1). The
+	// result is not simplified. Bound variables (e.g. the integration variable of an integral)
+	// are never replaced, and replacing a free variable with an expression involving a bound
+	// one is an error, since that variable would be captured.
+	Substitute(substitutions Substitutions) (Expression, error)
 	// Evaluate tries to evaluate the expression, recursively, given some arguments.
 	Evaluate(arguments Arguments) (sets.Number, error)
 	// Derivative generates a new expression being the derivative of the current one.
@@ -53,9 +61,9 @@ type Expression interface {
 	// E.g. (X + 3) is not a constant expression per se, but it is with respect to Y.
 	IsConstant(wrt Variable) bool
 	// Simplify generates a simplified expression of this one.
-	// Currently, this only converts to constants every expression involving only
-	// constants, and does not any heuristic regarding (x+1)/(x+1) or actual
-	// simplification processes.
+	// This converts to constants every expression involving only constants, drops
+	// identity elements, combines like terms in additions (X + X becomes 2 * X)
+	// and merges equal bases in multiplications (X * Y / X becomes Y).
 	Simplify() (Expression, error)
 	fmt.Stringer
 }
@@ -72,6 +80,19 @@ type SelfContained interface {
 }
 
 
+// isSelfContained tells whether an expression is self-contained in representation.
+// Negative constants are not, despite being constants: their leading minus sign
+// would be taken as a negation of the whole operation they are operands of.
+func isSelfContained(expression Expression) bool {
+	if constant, ok := expression.(Constant); ok {
+		return !ops.IsNegative(constant.number)
+	} else {
+		_, ok := expression.(SelfContained)
+		return ok
+	}
+}
+
+
 // CollectVariables adds the current variable to the set.
 func (variable Variable) CollectVariables(variables Variables) {
 	variables[variable] = true
@@ -103,6 +124,17 @@ func (variable Variable) Curry(args Arguments) (Expression, error) {
 }
 
 
+// Substitute returns the expression replacing this variable, if any, or the
+// same variable otherwise.
+func (variable Variable) Substitute(substitutions Substitutions) (Expression, error) {
+	if substitution, ok := substitutions[variable]; ok {
+		return substitution, nil
+	} else {
+		return variable, nil
+	}
+}
+
+
 // Evaluate just drags the appropriate value from the given arguments.
 // It returns an error if a value for the current variable is not present.
 func (variable Variable) Evaluate(args Arguments) (sets.Number, error) {
@@ -118,9 +150,9 @@ func (variable Variable) Evaluate(args Arguments) (sets.Number, error) {
 // The 0 will be in the case the variables are not the same.
 func (variable Variable) Derivative(wrt Variable) (Expression, error) {
 	if variable == wrt {
-		return Constant{one}, nil
+		return Num(1), nil
 	} else {
-		return Constant{zero}, nil
+		return Num(0), nil
 	}
 }
 
@@ -161,6 +193,12 @@ func (constant Constant) Curry(args Arguments) (Expression, error) {
 }
 
 
+// Substitute returns the same constant: there are no variables to replace.
+func (constant Constant) Substitute(substitutions Substitutions) (Expression, error) {
+	return constant, nil
+}
+
+
 // Evaluate ignores any argument and returns the constant's value.
 func (constant Constant) Evaluate(args Arguments) (sets.Number, error) {
 	return constant.number, nil
@@ -169,7 +207,7 @@ func (constant Constant) Evaluate(args Arguments) (sets.Number, error) {
 
 // Derivative is a 0 expression for any constant.
 func (constant Constant) Derivative(wrt Variable) (Expression, error) {
-	return Constant{zero}, nil
+	return Num(0), nil
 }
 
 
@@ -177,7 +215,7 @@ func (constant Constant) Derivative(wrt Variable) (Expression, error) {
 func (constant Constant) String() string {
 	switch c := constant.number.(type) {
 	case *big.Float:
-		return c.Text('f', -1)
+		return floatText(c)
 	case *big.Int:
 		return fmt.Sprintf("%d", c)
 	case *big.Rat:
@@ -187,6 +225,26 @@ func (constant Constant) String() string {
 }
 
 
+// floatText represents a float so it is parsed back as the same float: integral
+// values keep a decimal point (otherwise they would be parsed as integers), and
+// the exponent notation is used when the plain digits do not keep the value
+// (e.g. 1e+30, whose plain digits are those of 10^30 instead).
+func floatText(value *big.Float) string {
+	text := value.Text('f', -1)
+	if value.IsInf() {
+		return text
+	} else if !strings.ContainsRune(text, '.') {
+		text += ".0"
+	}
+	for _, candidate := range []string{text, value.Text('e', -1)} {
+		if parsed, ok := parseNumber(token{kind: floatToken, text: candidate}); ok && parsed.(*big.Float).Cmp(value) == 0 {
+			return candidate
+		}
+	}
+	return text
+}
+
+
 func (constant Constant) IsSelfContained() bool {
 	return true
 }
@@ -212,6 +270,16 @@ func Num(n interface{}) Constant {
 }
 
 
+// normalizedNumber converts integral rationals into integers, so simplified
+// results like 3/3 are represented as 1 instead of 1/1.
+func normalizedNumber(number sets.Number) sets.Number {
+	if rat, ok := number.(*big.Rat); ok && rat.IsInt() {
+		return big.NewInt(0).Set(rat.Num())
+	}
+	return number
+}
+
+
 // MakeExpression takes an arbitrary value and creates an expression out of it.
 // If the value was already an expression, it returns it as-is.
 // Otherwise, it makes a constant expression out of it.
@@ -84,6 +84,16 @@ func (factorial FactorialExpr) Curry(args Arguments) (Expression, error) {
 }
 
 
+// Substitute replaces the variables in the underlying expression, and rebuilds the node.
+func (factorial FactorialExpr) Substitute(substitutions Substitutions) (Expression, error) {
+	if substituted, err := factorial.arg.Substitute(substitutions); err != nil {
+		return nil, err
+	} else {
+		return Factorial(substituted), nil
+	}
+}
+
+
 // Evaluate computes the factorial over the evaluated inner argument's value.
 // It will be an error if the inner value does not evaluate into N0.
 func (factorial FactorialExpr) Evaluate(args Arguments) (sets.Number, error) {
@@ -97,7 +107,7 @@ func (factorial FactorialExpr) Evaluate(args Arguments) (sets.Number, error) {
 
 // String represents the factorial as x! or (x)! appropriately.
 func (factorial FactorialExpr) String() string {
-	if _, ok := factorial.arg.(SelfContained); ok {
+	if isSelfContained(factorial.arg) {
 		return fmt.Sprintf("%s!", factorial.arg)
 	} else {
 		return fmt.Sprintf("(%s)!", factorial.arg)
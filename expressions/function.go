@@ -3,6 +3,9 @@ package expressions
 import (
 	"strings"
 	"fmt"
+	"math/big"
+	"github.com/universe-10th/calculus/errors"
+	"github.com/universe-10th/calculus/ops"
 )
 
 
@@ -52,4 +55,103 @@ func FunctionDisplay(function Function) string {
 		fmtArgs[index + 1] = argument
 	}
 	return fmt.Sprintf(fmtString, fmtArgs...)
-}
\ No newline at end of file
+}
+
+// FunctionBuilder constructs a function node out of its arguments. It must
+// fail with an error (typically errors.ErrWrongArgumentsCount) if the given
+// arguments do not fit the function.
+type FunctionBuilder func(arguments ...Expression) (Expression, error)
+
+
+var functionBuilders = map[string]FunctionBuilder{}
+
+
+// RegisterFunction registers a builder under a function name, so the function
+// can be later looked up by name (e.g. when parsing). Names are case-insensitive
+// and registering an already-registered name replaces the former builder.
+func RegisterFunction(name string, builder FunctionBuilder) {
+	functionBuilders[strings.ToLower(name)] = builder
+}
+
+
+// LookupFunction returns the builder registered for the given function name.
+func LookupFunction(name string) (FunctionBuilder, bool) {
+	builder, ok := functionBuilders[strings.ToLower(name)]
+	return builder, ok
+}
+
+
+// unary wraps a single-argument constructor into a function builder.
+func unary(constructor func(Expression) Expression) FunctionBuilder {
+	return func(arguments ...Expression) (Expression, error) {
+		if len(arguments) != 1 {
+			return nil, errors.ErrWrongArgumentsCount
+		}
+		return constructor(arguments[0]), nil
+	}
+}
+
+
+// binary wraps a two-arguments constructor into a function builder.
+func binary(constructor func(Expression, Expression) Expression) FunctionBuilder {
+	return func(arguments ...Expression) (Expression, error) {
+		if len(arguments) != 2 {
+			return nil, errors.ErrWrongArgumentsCount
+		}
+		return constructor(arguments[0], arguments[1]), nil
+	}
+}
+
+
+func buildRound(arguments ...Expression) (Expression, error) {
+	if len(arguments) != 2 {
+		return nil, errors.ErrWrongArgumentsCount
+	}
+	if constant, ok := arguments[1].(Constant); !ok {
+		return nil, errors.ErrInvalidRoundType
+	} else if value, ok := constant.number.(*big.Int); !ok || !value.IsInt64() {
+		return nil, errors.ErrInvalidRoundType
+	} else if roundType := ops.RoundType(value.Int64()); roundType < ops.Ceil || roundType > ops.Outward {
+		return nil, errors.ErrInvalidRoundType
+	} else {
+		return Round{arguments[0], roundType}, nil
+	}
+}
+
+
+func buildDefectiveOnInt(arguments ...Expression) (Expression, error) {
+	if len(arguments) != 2 {
+		return nil, errors.ErrWrongArgumentsCount
+	}
+	if constant, ok := arguments[1].(Constant); !ok {
+		return nil, errors.ErrInvalidArgument
+	} else {
+		return DefectiveOnInt{arguments[0], constant.number}, nil
+	}
+}
+
+
+func init() {
+	RegisterFunction("ln", unary(Ln))
+	RegisterFunction("log", binary(Log))
+	RegisterFunction("exp", unary(Exp))
+	RegisterFunction("sin", unary(Sin))
+	RegisterFunction("cos", unary(Cos))
+	RegisterFunction("tan", unary(Tan))
+	RegisterFunction("sec", unary(Sec))
+	RegisterFunction("csc", unary(Csc))
+	RegisterFunction("cot", unary(Cot))
+	RegisterFunction("arcsin", unary(ArcSin))
+	RegisterFunction("arccos", unary(ArcCos))
+	RegisterFunction("arctan", unary(ArcTan))
+	RegisterFunction("arctan2", binary(ArcTan2))
+	RegisterFunction("sinh", unary(Sinh))
+	RegisterFunction("cosh", unary(Cosh))
+	RegisterFunction("tanh", unary(Tanh))
+	RegisterFunction("arcsinh", unary(ArcSinh))
+	RegisterFunction("arccosh", unary(ArcCosh))
+	RegisterFunction("arctanh", unary(ArcTanh))
+	RegisterFunction("round", buildRound)
+	RegisterFunction("frac", unary(func(arg Expression) Expression { return Frac{arg} }))
+	RegisterFunction("doi", buildDefectiveOnInt)
+}
@@ -51,12 +51,10 @@ type NRGoalSeekAlgorithmArgsProvider func(arguments expressions.Arguments) (
 )
 
 
-// Builds a new Newton-Raphson algorithm.
-func NRGoalSeek(
-	goal, target expressions.Expression, invertedVariable expressions.Variable,
-	argsProvider NRGoalSeekAlgorithmArgsProvider,
-) expressions.Expression {
-	return expressions.GoalSeek(goal, target, invertedVariable, func(
+// Builds a factory of Newton-Raphson algorithms. Such factory can
+// be registered (under any name) to build named goal-seeks.
+func NRGoalSeekFactory(argsProvider NRGoalSeekAlgorithmArgsProvider) expressions.GoalSeekingAlgorithmFactory {
+	return func(
 		arguments expressions.Arguments, inverted expressions.Variable, fullDomain expressions.Variables,
 	) (expressions.GoalSeekingAlgorithm, error) {
 		initial, epsilon, maxIterations, maxCorrections := argsProvider(arguments)
@@ -69,5 +67,14 @@ func NRGoalSeek(
 				inverted,
 			}, nil
 		}
-	})
+	}
+}
+
+
+// Builds a new Newton-Raphson algorithm.
+func NRGoalSeek(
+	goal, target expressions.Expression, invertedVariable expressions.Variable,
+	argsProvider NRGoalSeekAlgorithmArgsProvider,
+) expressions.Expression {
+	return expressions.GoalSeek(goal, target, invertedVariable, NRGoalSeekFactory(argsProvider))
 }
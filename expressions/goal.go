@@ -52,6 +52,9 @@ type GoalSeekExpr struct {
 	// The algorithm factory that we'll use to instantiate the
 	// engine that will be used as goal-seeker.
 	factory GoalSeekingAlgorithmFactory
+	// The name the factory was registered with, if any. It is
+	// empty when the expression was built with an unnamed one.
+	factoryName string
 }
 
 
@@ -74,10 +77,9 @@ func (goalSeekExpr GoalSeekExpr) Simplify() (Expression, error) {
 	} else {
 		_, okGoal := simplifiedGoal.(Constant)
 		_, okTarget := simplifiedTarget.(Constant)
-		simplifiedExpr := GoalSeek(simplifiedGoal, simplifiedTarget, goalSeekExpr.inverted, goalSeekExpr.factory)
+		simplifiedExpr := goalSeekExpr.rebuild(simplifiedGoal, simplifiedTarget)
 		if okTarget && okGoal {
-			// Create a dummy one, and evaluate with no arguments.
-			simplifiedExpr = GoalSeek(simplifiedGoal, simplifiedTarget, goalSeekExpr.inverted, goalSeekExpr.factory)
+			// Evaluate the dummy one with no arguments.
 			if result, err := simplifiedExpr.Evaluate(Arguments{}); err != nil {
 				return nil, err
 			} else {
@@ -112,10 +114,40 @@ func (goalSeekExpr GoalSeekExpr) IsConstant(wrt Variable) bool {
 func (goalSeekExpr GoalSeekExpr) Curry(args Arguments) (Expression, error) {
 	if curriedGoal, err := goalSeekExpr.goal.Curry(args); err != nil {
 		return nil, err
-	} else 	if curriedTarget, err := goalSeekExpr.goal.Curry(goalSeekExpr.getNonInvertedArguments(args)); err != nil {
+	} else 	if curriedTarget, err := goalSeekExpr.target.Curry(goalSeekExpr.getNonInvertedArguments(args)); err != nil {
 		return nil, err
 	} else {
-		return GoalSeek(curriedGoal, curriedTarget, goalSeekExpr.inverted, goalSeekExpr.factory).Simplify()
+		return goalSeekExpr.rebuild(curriedGoal, curriedTarget).Simplify()
+	}
+}
+
+
+// Substitute replaces the variables in the goal, and in the target. The inverted
+// variable is never replaced in the target, since it is the one being found, and
+// no variable in the target may be replaced with an expression involving it.
+func (goalSeekExpr GoalSeekExpr) Substitute(substitutions Substitutions) (Expression, error) {
+	if substitutedGoal, err := goalSeekExpr.goal.Substitute(substitutions); err != nil {
+		return nil, err
+	} else if targetSubstitutions, err := boundSubstitutions(
+		substitutions, goalSeekExpr.inverted, goalSeekExpr.target,
+	); err != nil {
+		return nil, err
+	} else if substitutedTarget, err := goalSeekExpr.target.Substitute(targetSubstitutions); err != nil {
+		return nil, err
+	} else {
+		return goalSeekExpr.rebuild(substitutedGoal, substitutedTarget), nil
+	}
+}
+
+
+// Creates a new goal-seek expression with the same inverted
+// variable and algorithm, but the given goal and target.
+func (goalSeekExpr GoalSeekExpr) rebuild(goal, target Expression) Expression {
+	targetDomain := Variables{}
+	target.CollectVariables(targetDomain)
+	return GoalSeekExpr{
+		goal, targetDomain, target, goalSeekExpr.inverted,
+		goalSeekExpr.factory, goalSeekExpr.factoryName,
 	}
 }
 
@@ -162,5 +194,36 @@ func (goalSeekExpr GoalSeekExpr) IsSelfContained() bool {
 func GoalSeek(goal, target Expression, inverted Variable, factory GoalSeekingAlgorithmFactory) Expression {
 	targetDomain := Variables{}
 	target.CollectVariables(targetDomain)
-	return GoalSeekExpr{goal, targetDomain,target, inverted, factory}
+	return GoalSeekExpr{goal, targetDomain,target, inverted, factory, ""}
+}
+
+
+var goalSeekingAlgorithmFactories = map[string]GoalSeekingAlgorithmFactory{}
+
+
+// RegisterGoalSeekingAlgorithmFactory registers an algorithm factory under a
+// name, so goal-seek expressions can refer to it by name (e.g. when they are
+// encoded). Registering an already-registered name replaces the former factory.
+func RegisterGoalSeekingAlgorithmFactory(name string, factory GoalSeekingAlgorithmFactory) {
+	goalSeekingAlgorithmFactories[name] = factory
+}
+
+
+// LookupGoalSeekingAlgorithmFactory returns the factory registered for the given name.
+func LookupGoalSeekingAlgorithmFactory(name string) (GoalSeekingAlgorithmFactory, bool) {
+	factory, ok := goalSeekingAlgorithmFactories[name]
+	return factory, ok
+}
+
+
+// NamedGoalSeek constructs a goal-seek expression using a registered algorithm
+// factory. Unlike the ones created by GoalSeek, these expressions can be encoded.
+func NamedGoalSeek(goal, target Expression, inverted Variable, factoryName string) (Expression, error) {
+	if factory, ok := LookupGoalSeekingAlgorithmFactory(factoryName); !ok {
+		return nil, errors.ErrUnregisteredGoalSeekingAlgorithm
+	} else {
+		targetDomain := Variables{}
+		target.CollectVariables(targetDomain)
+		return GoalSeekExpr{goal, targetDomain,target, inverted, factory, factoryName}, nil
+	}
 }
\ No newline at end of file
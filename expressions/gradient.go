@@ -0,0 +1,151 @@
+package expressions
+
+import (
+	"sort"
+	"strings"
+	"github.com/universe-10th/calculus/sets"
+)
+
+
+// Vector is an ordered list of expressions (e.g. a gradient).
+type Vector []Expression
+
+
+// Matrix is an ordered list of rows of expressions (e.g. a Jacobian).
+type Matrix []Vector
+
+
+// SortedVariables collects the variables of all the given expressions and
+// sorts them by name, so they have a stable order across runs.
+func SortedVariables(expressions ...Expression) []Variable {
+	variables := Variables{}
+	for _, expression := range expressions {
+		expression.CollectVariables(variables)
+	}
+	result := make([]Variable, 0, len(variables))
+	for variable := range variables {
+		result = append(result, variable)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].name < result[j].name
+	})
+	return result
+}
+
+
+// Gradient computes the partial derivatives of an expression with respect to
+// each of the given variables, in the same order. When no variables are given,
+// the ones in the expression are used, in the order SortedVariables tells.
+func Gradient(expression Expression, variables []Variable) (Vector, error) {
+	if variables == nil {
+		variables = SortedVariables(expression)
+	}
+	gradient := make(Vector, len(variables))
+	for index, variable := range variables {
+		if derivative, err := expression.Derivative(variable); err != nil {
+			return nil, err
+		} else {
+			gradient[index] = derivative
+		}
+	}
+	return gradient, nil
+}
+
+
+// Jacobian computes the gradients of each of the given expressions (one row
+// per expression), with respect to the given variables, in the same order.
+// When no variables are given, the ones in all the expressions are used, in
+// the order SortedVariables tells.
+func Jacobian(expressions []Expression, variables []Variable) (Matrix, error) {
+	if variables == nil {
+		variables = SortedVariables(expressions...)
+	}
+	jacobian := make(Matrix, len(expressions))
+	for index, expression := range expressions {
+		if gradient, err := Gradient(expression, variables); err != nil {
+			return nil, err
+		} else {
+			jacobian[index] = gradient
+		}
+	}
+	return jacobian, nil
+}
+
+
+// Hessian computes the second-order partial derivatives of an expression with
+// respect to each pair of the given variables, in the same order. When no
+// variables are given, the ones in the expression are used, in the order
+// SortedVariables tells. The mixed derivatives are computed once and mirrored,
+// assuming they are continuous (so the matrix is symmetric).
+func Hessian(expression Expression, variables []Variable) (Matrix, error) {
+	if variables == nil {
+		variables = SortedVariables(expression)
+	}
+	gradient, err := Gradient(expression, variables)
+	if err != nil {
+		return nil, err
+	}
+	hessian := make(Matrix, len(variables))
+	for row := range hessian {
+		hessian[row] = make(Vector, len(variables))
+	}
+	for row, partial := range gradient {
+		for column := row; column < len(variables); column++ {
+			if derivative, err := partial.Derivative(variables[column]); err != nil {
+				return nil, err
+			} else {
+				hessian[row][column] = derivative
+				hessian[column][row] = derivative
+			}
+		}
+	}
+	return hessian, nil
+}
+
+
+// Evaluate evaluates each of the expressions in the vector.
+func (vector Vector) Evaluate(arguments Arguments) ([]sets.Number, error) {
+	result := make([]sets.Number, len(vector))
+	for index, expression := range vector {
+		if value, err := expression.Evaluate(arguments); err != nil {
+			return nil, err
+		} else {
+			result[index] = value
+		}
+	}
+	return result, nil
+}
+
+
+// Evaluate evaluates each of the expressions in the matrix, row by row.
+func (matrix Matrix) Evaluate(arguments Arguments) ([][]sets.Number, error) {
+	result := make([][]sets.Number, len(matrix))
+	for index, row := range matrix {
+		if values, err := row.Evaluate(arguments); err != nil {
+			return nil, err
+		} else {
+			result[index] = values
+		}
+	}
+	return result, nil
+}
+
+
+// String represents the vector as a bracketed, comma-separated, list.
+func (vector Vector) String() string {
+	elements := make([]string, len(vector))
+	for index, expression := range vector {
+		elements[index] = expression.String()
+	}
+	return "[" + strings.Join(elements, ", ") + "]"
+}
+
+
+// String represents the matrix as a bracketed list of its rows.
+func (matrix Matrix) String() string {
+	rows := make([]string, len(matrix))
+	for index, row := range matrix {
+		rows[index] = row.String()
+	}
+	return "[" + strings.Join(rows, ", ") + "]"
+}
@@ -0,0 +1,423 @@
+package expressions
+
+import (
+	"math/big"
+	"github.com/universe-10th/calculus/sets"
+	"github.com/universe-10th/calculus/ops"
+	"github.com/universe-10th/calculus/errors"
+)
+
+
+// Hyperbolic functions are computed in arbitrary precision, on top of ops.Exp
+// and ops.Ln, so they keep the precision of their arguments.
+
+
+// SinhExpr stands for a hyperbolic sine expression.
+type SinhExpr struct {
+	TrigFunctionExpr
+}
+
+
+// CoshExpr stands for a hyperbolic cosine expression.
+type CoshExpr struct {
+	TrigFunctionExpr
+}
+
+
+// TanhExpr stands for a hyperbolic tangent expression.
+type TanhExpr struct {
+	TrigFunctionExpr
+}
+
+
+// ArcSinhExpr stands for an inverse hyperbolic sine expression.
+type ArcSinhExpr struct {
+	TrigFunctionExpr
+}
+
+
+// ArcCoshExpr stands for an inverse hyperbolic cosine expression.
+type ArcCoshExpr struct {
+	TrigFunctionExpr
+}
+
+
+// ArcTanhExpr stands for an inverse hyperbolic tangent expression.
+type ArcTanhExpr struct {
+	TrigFunctionExpr
+}
+
+
+// Simplify attempts reducing a hyperbolic sine expression to a constant.
+// Since it is an odd function, sinh(-x) becomes -sinh(x).
+func (sinh SinhExpr) Simplify() (Expression, error) {
+	if simplified, err := sinh.arg.Simplify(); err != nil {
+		return nil, err
+	} else if num, ok := simplified.(Constant); ok {
+		return Constant{ops.Sinh(num.number)}, nil
+	} else if negated, ok := simplified.(NegatedExpr); ok {
+		return Negated(Sinh(negated.arg)), nil
+	} else {
+		return Sinh(simplified), nil
+	}
+}
+
+
+// Curry tries currying the underlying expression first, and then attempts simplifying.
+func (sinh SinhExpr) Curry(args Arguments) (Expression, error) {
+	if curried, err := sinh.arg.Curry(args); err != nil {
+		return nil, err
+	} else {
+		return Sinh(curried).Simplify()
+	}
+}
+
+
+// Substitute replaces the variables in the underlying expression, and rebuilds the node.
+func (sinh SinhExpr) Substitute(substitutions Substitutions) (Expression, error) {
+	if substituted, err := sinh.arg.Substitute(substitutions); err != nil {
+		return nil, err
+	} else {
+		return Sinh(substituted), nil
+	}
+}
+
+
+// Evaluate computes the hyperbolic sine over the evaluated value of the inner expression.
+func (sinh SinhExpr) Evaluate(args Arguments) (sets.Number, error) {
+	if result, err := sinh.arg.Evaluate(args); err == nil {
+		return ops.Sinh(result), nil
+	} else {
+		return nil, err
+	}
+}
+
+
+// Derivative uses the hyperbolic sine rule (cosh(x)) and also applies the chain rule.
+func (sinh SinhExpr) Derivative(wrt Variable) (Expression, error) {
+	if derivative, err := sinh.arg.Derivative(wrt); err != nil {
+		return nil, err
+	} else {
+		return Mul(Cosh(sinh.arg), derivative).Simplify()
+	}
+}
+
+
+// Simplify attempts reducing a hyperbolic cosine expression to a constant.
+// Since it is an even function, cosh(-x) becomes cosh(x).
+func (cosh CoshExpr) Simplify() (Expression, error) {
+	if simplified, err := cosh.arg.Simplify(); err != nil {
+		return nil, err
+	} else if num, ok := simplified.(Constant); ok {
+		return Constant{ops.Cosh(num.number)}, nil
+	} else if negated, ok := simplified.(NegatedExpr); ok {
+		return Cosh(negated.arg), nil
+	} else {
+		return Cosh(simplified), nil
+	}
+}
+
+
+// Curry tries currying the underlying expression first, and then attempts simplifying.
+func (cosh CoshExpr) Curry(args Arguments) (Expression, error) {
+	if curried, err := cosh.arg.Curry(args); err != nil {
+		return nil, err
+	} else {
+		return Cosh(curried).Simplify()
+	}
+}
+
+
+// Substitute replaces the variables in the underlying expression, and rebuilds the node.
+func (cosh CoshExpr) Substitute(substitutions Substitutions) (Expression, error) {
+	if substituted, err := cosh.arg.Substitute(substitutions); err != nil {
+		return nil, err
+	} else {
+		return Cosh(substituted), nil
+	}
+}
+
+
+// Evaluate computes the hyperbolic cosine over the evaluated value of the inner expression.
+func (cosh CoshExpr) Evaluate(args Arguments) (sets.Number, error) {
+	if result, err := cosh.arg.Evaluate(args); err == nil {
+		return ops.Cosh(result), nil
+	} else {
+		return nil, err
+	}
+}
+
+
+// Derivative uses the hyperbolic cosine rule (sinh(x)) and also applies the chain rule.
+func (cosh CoshExpr) Derivative(wrt Variable) (Expression, error) {
+	if derivative, err := cosh.arg.Derivative(wrt); err != nil {
+		return nil, err
+	} else {
+		return Mul(Sinh(cosh.arg), derivative).Simplify()
+	}
+}
+
+
+// Simplify attempts reducing a hyperbolic tangent expression to a constant.
+// Since it is an odd function, tanh(-x) becomes -tanh(x).
+func (tanh TanhExpr) Simplify() (Expression, error) {
+	if simplified, err := tanh.arg.Simplify(); err != nil {
+		return nil, err
+	} else if num, ok := simplified.(Constant); ok {
+		return Constant{ops.Tanh(num.number)}, nil
+	} else if negated, ok := simplified.(NegatedExpr); ok {
+		return Negated(Tanh(negated.arg)), nil
+	} else {
+		return Tanh(simplified), nil
+	}
+}
+
+
+// Curry tries currying the underlying expression first, and then attempts simplifying.
+func (tanh TanhExpr) Curry(args Arguments) (Expression, error) {
+	if curried, err := tanh.arg.Curry(args); err != nil {
+		return nil, err
+	} else {
+		return Tanh(curried).Simplify()
+	}
+}
+
+
+// Substitute replaces the variables in the underlying expression, and rebuilds the node.
+func (tanh TanhExpr) Substitute(substitutions Substitutions) (Expression, error) {
+	if substituted, err := tanh.arg.Substitute(substitutions); err != nil {
+		return nil, err
+	} else {
+		return Tanh(substituted), nil
+	}
+}
+
+
+// Evaluate computes the hyperbolic tangent over the evaluated value of the inner expression.
+func (tanh TanhExpr) Evaluate(args Arguments) (sets.Number, error) {
+	if result, err := tanh.arg.Evaluate(args); err == nil {
+		return ops.Tanh(result), nil
+	} else {
+		return nil, err
+	}
+}
+
+
+// Derivative uses the hyperbolic tangent rule (1/cosh(x)^2) and also applies the chain rule.
+func (tanh TanhExpr) Derivative(wrt Variable) (Expression, error) {
+	if derivative, err := tanh.arg.Derivative(wrt); err != nil {
+		return nil, err
+	} else {
+		return Mul(Pow(Cosh(tanh.arg), Num(-2)), derivative).Simplify()
+	}
+}
+
+
+// Simplify attempts reducing an inverse hyperbolic sine expression to a constant.
+// Since it is an odd function, arcsinh(-x) becomes -arcsinh(x).
+func (arcSinh ArcSinhExpr) Simplify() (Expression, error) {
+	if simplified, err := arcSinh.arg.Simplify(); err != nil {
+		return nil, err
+	} else if num, ok := simplified.(Constant); ok {
+		return Constant{ops.ArcSinh(num.number)}, nil
+	} else if negated, ok := simplified.(NegatedExpr); ok {
+		return Negated(ArcSinh(negated.arg)), nil
+	} else {
+		return ArcSinh(simplified), nil
+	}
+}
+
+
+// Curry tries currying the underlying expression first, and then attempts simplifying.
+func (arcSinh ArcSinhExpr) Curry(args Arguments) (Expression, error) {
+	if curried, err := arcSinh.arg.Curry(args); err != nil {
+		return nil, err
+	} else {
+		return ArcSinh(curried).Simplify()
+	}
+}
+
+
+// Substitute replaces the variables in the underlying expression, and rebuilds the node.
+func (arcSinh ArcSinhExpr) Substitute(substitutions Substitutions) (Expression, error) {
+	if substituted, err := arcSinh.arg.Substitute(substitutions); err != nil {
+		return nil, err
+	} else {
+		return ArcSinh(substituted), nil
+	}
+}
+
+
+// Evaluate computes the inverse hyperbolic sine over the evaluated value of the inner expression.
+func (arcSinh ArcSinhExpr) Evaluate(args Arguments) (sets.Number, error) {
+	if result, err := arcSinh.arg.Evaluate(args); err == nil {
+		return ops.ArcSinh(result), nil
+	} else {
+		return nil, err
+	}
+}
+
+
+// Derivative uses the inverse hyperbolic sine rule (1/sqrt(x^2 + 1)) and also applies the chain rule.
+func (arcSinh ArcSinhExpr) Derivative(wrt Variable) (Expression, error) {
+	if derivative, err := arcSinh.arg.Derivative(wrt); err != nil {
+		return nil, err
+	} else {
+		return Div(derivative, Pow(Add(Pow(arcSinh.arg, Num(2)), Num(1)), Num(big.NewRat(1, 2)))).Simplify()
+	}
+}
+
+
+// Simplify attempts reducing an inverse hyperbolic cosine expression to a constant.
+// It is an error if the constant is out of the domain.
+func (arcCosh ArcCoshExpr) Simplify() (Expression, error) {
+	if simplified, err := arcCosh.arg.Simplify(); err != nil {
+		return nil, err
+	} else if num, ok := simplified.(Constant); ok {
+		if result, err := wrappedTrig(ops.ArcCosh, num.number, errors.ErrArcHyperbolicOutOfDomain); err != nil {
+			return nil, err
+		} else {
+			return Constant{result}, nil
+		}
+	} else {
+		return ArcCosh(simplified), nil
+	}
+}
+
+
+// Curry tries currying the underlying expression first, and then attempts simplifying.
+func (arcCosh ArcCoshExpr) Curry(args Arguments) (Expression, error) {
+	if curried, err := arcCosh.arg.Curry(args); err != nil {
+		return nil, err
+	} else {
+		return ArcCosh(curried).Simplify()
+	}
+}
+
+
+// Substitute replaces the variables in the underlying expression, and rebuilds the node.
+func (arcCosh ArcCoshExpr) Substitute(substitutions Substitutions) (Expression, error) {
+	if substituted, err := arcCosh.arg.Substitute(substitutions); err != nil {
+		return nil, err
+	} else {
+		return ArcCosh(substituted), nil
+	}
+}
+
+
+// Evaluate computes the inverse hyperbolic cosine over the evaluated value of the inner expression.
+// It is an error if the value is out of the domain.
+func (arcCosh ArcCoshExpr) Evaluate(args Arguments) (sets.Number, error) {
+	if result, err := arcCosh.arg.Evaluate(args); err == nil {
+		return wrappedTrig(ops.ArcCosh, result, errors.ErrArcHyperbolicOutOfDomain)
+	} else {
+		return nil, err
+	}
+}
+
+
+// Derivative uses the inverse hyperbolic cosine rule (1/sqrt(x^2 - 1)) and also applies the chain rule.
+func (arcCosh ArcCoshExpr) Derivative(wrt Variable) (Expression, error) {
+	if derivative, err := arcCosh.arg.Derivative(wrt); err != nil {
+		return nil, err
+	} else {
+		return Div(derivative, Pow(Sub(Pow(arcCosh.arg, Num(2)), Num(1)), Num(big.NewRat(1, 2)))).Simplify()
+	}
+}
+
+
+// Simplify attempts reducing an inverse hyperbolic tangent expression to a constant.
+// It is an error if the constant is out of the domain.
+// Since it is an odd function, arctanh(-x) becomes -arctanh(x).
+func (arcTanh ArcTanhExpr) Simplify() (Expression, error) {
+	if simplified, err := arcTanh.arg.Simplify(); err != nil {
+		return nil, err
+	} else if num, ok := simplified.(Constant); ok {
+		if result, err := wrappedTrig(ops.ArcTanh, num.number, errors.ErrArcHyperbolicOutOfDomain); err != nil {
+			return nil, err
+		} else {
+			return Constant{result}, nil
+		}
+	} else if negated, ok := simplified.(NegatedExpr); ok {
+		return Negated(ArcTanh(negated.arg)), nil
+	} else {
+		return ArcTanh(simplified), nil
+	}
+}
+
+
+// Curry tries currying the underlying expression first, and then attempts simplifying.
+func (arcTanh ArcTanhExpr) Curry(args Arguments) (Expression, error) {
+	if curried, err := arcTanh.arg.Curry(args); err != nil {
+		return nil, err
+	} else {
+		return ArcTanh(curried).Simplify()
+	}
+}
+
+
+// Substitute replaces the variables in the underlying expression, and rebuilds the node.
+func (arcTanh ArcTanhExpr) Substitute(substitutions Substitutions) (Expression, error) {
+	if substituted, err := arcTanh.arg.Substitute(substitutions); err != nil {
+		return nil, err
+	} else {
+		return ArcTanh(substituted), nil
+	}
+}
+
+
+// Evaluate computes the inverse hyperbolic tangent over the evaluated value of the inner expression.
+// It is an error if the value is out of the domain.
+func (arcTanh ArcTanhExpr) Evaluate(args Arguments) (sets.Number, error) {
+	if result, err := arcTanh.arg.Evaluate(args); err == nil {
+		return wrappedTrig(ops.ArcTanh, result, errors.ErrArcHyperbolicOutOfDomain)
+	} else {
+		return nil, err
+	}
+}
+
+
+// Derivative uses the inverse hyperbolic tangent rule (1/(1 - x^2)) and also applies the chain rule.
+func (arcTanh ArcTanhExpr) Derivative(wrt Variable) (Expression, error) {
+	if derivative, err := arcTanh.arg.Derivative(wrt); err != nil {
+		return nil, err
+	} else {
+		return Div(derivative, Sub(Num(1), Pow(arcTanh.arg, Num(2)))).Simplify()
+	}
+}
+
+
+// Sinh constructs a hyperbolic sine expression.
+func Sinh(arg Expression) Expression {
+	return SinhExpr{TrigFunctionExpr{FunctionExpr{"sinh"},arg}}
+}
+
+
+// Cosh constructs a hyperbolic cosine expression.
+func Cosh(arg Expression) Expression {
+	return CoshExpr{TrigFunctionExpr{FunctionExpr{"cosh"},arg}}
+}
+
+
+// Tanh constructs a hyperbolic tangent expression.
+func Tanh(arg Expression) Expression {
+	return TanhExpr{TrigFunctionExpr{FunctionExpr{"tanh"},arg}}
+}
+
+
+// ArcSinh constructs an inverse hyperbolic sine expression.
+func ArcSinh(arg Expression) Expression {
+	return ArcSinhExpr{TrigFunctionExpr{FunctionExpr{"arcsinh"},arg}}
+}
+
+
+// ArcCosh constructs an inverse hyperbolic cosine expression.
+func ArcCosh(arg Expression) Expression {
+	return ArcCoshExpr{TrigFunctionExpr{FunctionExpr{"arccosh"},arg}}
+}
+
+
+// ArcTanh constructs an inverse hyperbolic tangent expression.
+func ArcTanh(arg Expression) Expression {
+	return ArcTanhExpr{TrigFunctionExpr{FunctionExpr{"arctanh"},arg}}
+}
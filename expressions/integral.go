@@ -0,0 +1,260 @@
+package expressions
+
+import (
+	"fmt"
+	"github.com/universe-10th/calculus/errors"
+	"github.com/universe-10th/calculus/sets"
+)
+
+
+// Integration algorithm engines will have only one method
+// to care about: estimating the definite integral of an
+// expression between two bounds. The expression is already
+// curried, with only the integration variable left.
+type IntegrationAlgorithm interface {
+	Integrate(integrand Expression, lower, upper sets.Number) (sets.Number, error)
+}
+
+
+// Engine factories take some arguments (related to the
+// intended evaluation and variables) and return an instance
+// of integration algorithm.
+type IntegrationAlgorithmFactory func(arguments Arguments, wrt Variable) (IntegrationAlgorithm, error)
+
+
+// Definite integrals are expressions involving an integrand,
+// the variable it is integrated with respect to, and the two
+// bounds. They are evaluated in three steps:
+// 1. Evaluate the bounds.
+// 2. Curry the integrand with all the arguments, but the one
+//    for the integration variable (which is bound).
+// 3. Pass the curried integrand and the evaluated bounds to
+//    the underlying algorithm.
+type IntegralExpr struct {
+	integrand Expression
+	// The integration variable. It is bound: it does not take
+	// part of the variables the integral depends on.
+	wrt          Variable
+	lower, upper Expression
+	// The algorithm factory that we'll use to instantiate the
+	// engine that will compute the integral.
+	factory IntegrationAlgorithmFactory
+	// The name the factory was registered with, if any. It is
+	// empty when the expression was built with an unnamed one.
+	factoryName string
+}
+
+
+// Derivative applies Leibniz's integral rule: the integral of the integrand's
+// derivative (differentiating under the integral sign), plus the integrand at
+// the upper bound times the upper bound's derivative, minus the integrand at
+// the lower bound times the lower bound's derivative.
+func (integral IntegralExpr) Derivative(wrt Variable) (Expression, error) {
+	if integral.IsConstant(wrt) {
+		return Num(0), nil
+	}
+	terms := []Expression{}
+	// Inside the integrand, the integration variable is bound: it is constant
+	// with respect to any outer variable having the same name.
+	if wrt != integral.wrt && !integral.integrand.IsConstant(wrt) {
+		if derivative, err := integral.integrand.Derivative(wrt); err != nil {
+			return nil, err
+		} else {
+			terms = append(terms, integral.rebuild(derivative, integral.lower, integral.upper))
+		}
+	}
+	if term, err := integral.boundTerm(integral.upper, wrt); err != nil {
+		return nil, err
+	} else if term != nil {
+		terms = append(terms, term)
+	}
+	if term, err := integral.boundTerm(integral.lower, wrt); err != nil {
+		return nil, err
+	} else if term != nil {
+		terms = append(terms, Negated(term))
+	}
+	return Add(terms...).Simplify()
+}
+
+
+// boundTerm computes the integrand at the given bound, times the derivative
+// of the bound. It returns nil if the bound is constant.
+func (integral IntegralExpr) boundTerm(bound Expression, wrt Variable) (Expression, error) {
+	if bound.IsConstant(wrt) {
+		return nil, nil
+	} else if derivative, err := bound.Derivative(wrt); err != nil {
+		return nil, err
+	} else if atBound, err := integral.integrand.Substitute(Substitutions{integral.wrt: bound}); err != nil {
+		return nil, err
+	} else {
+		return Mul(atBound, derivative), nil
+	}
+}
+
+
+// Simplifying simplifies the integrand and bounds and, if only depending on constants, evaluates it.
+func (integral IntegralExpr) Simplify() (Expression, error) {
+	if simplifiedIntegrand, err := integral.integrand.Simplify(); err != nil {
+		return nil, err
+	} else if simplifiedLower, err := integral.lower.Simplify(); err != nil {
+		return nil, err
+	} else if simplifiedUpper, err := integral.upper.Simplify(); err != nil {
+		return nil, err
+	} else {
+		simplifiedExpr := integral.rebuild(simplifiedIntegrand, simplifiedLower, simplifiedUpper)
+		_, okLower := simplifiedLower.(Constant)
+		_, okUpper := simplifiedUpper.(Constant)
+		variables := Variables{}
+		simplifiedExpr.CollectVariables(variables)
+		if okLower && okUpper && len(variables) == 0 {
+			// Evaluate the dummy one with no arguments.
+			if result, err := simplifiedExpr.Evaluate(Arguments{}); err != nil {
+				return nil, err
+			} else {
+				return Num(result), nil
+			}
+		} else {
+			return simplifiedExpr, nil
+		}
+	}
+}
+
+
+// CollectVariables digs into the integrand's variables (except for the integration
+// one), and also the bounds' variables.
+func (integral IntegralExpr) CollectVariables(variables Variables) {
+	integrandVariables := Variables{}
+	integral.integrand.CollectVariables(integrandVariables)
+	delete(integrandVariables, integral.wrt)
+	for variable := range integrandVariables {
+		variables[variable] = true
+	}
+	integral.lower.CollectVariables(variables)
+	integral.upper.CollectVariables(variables)
+}
+
+
+// IsConstant will return whether the integral is constant with respect to the given variable.
+// This check involves two requirements:
+// - The bounds are constant with respect to the variable.
+// - The integrand is constant with respect to the variable, or the variable is the integration one.
+func (integral IntegralExpr) IsConstant(wrt Variable) bool {
+	return integral.lower.IsConstant(wrt) && integral.upper.IsConstant(wrt) &&
+		(integral.wrt == wrt || integral.integrand.IsConstant(wrt))
+}
+
+
+// Curry tries currying the underlying expressions first, and then attempts simplifying.
+// The integration variable is never curried in the integrand.
+func (integral IntegralExpr) Curry(args Arguments) (Expression, error) {
+	if curriedIntegrand, err := integral.integrand.Curry(integral.getNonBoundArguments(args)); err != nil {
+		return nil, err
+	} else if curriedLower, err := integral.lower.Curry(args); err != nil {
+		return nil, err
+	} else if curriedUpper, err := integral.upper.Curry(args); err != nil {
+		return nil, err
+	} else {
+		return integral.rebuild(curriedIntegrand, curriedLower, curriedUpper).Simplify()
+	}
+}
+
+
+// Substitute replaces the variables in the integrand and the bounds. The
+// integration variable is never replaced in the integrand, and no variable
+// in the integrand may be replaced with an expression involving it.
+func (integral IntegralExpr) Substitute(substitutions Substitutions) (Expression, error) {
+	if integrandSubstitutions, err := boundSubstitutions(
+		substitutions, integral.wrt, integral.integrand,
+	); err != nil {
+		return nil, err
+	} else if substitutedIntegrand, err := integral.integrand.Substitute(integrandSubstitutions); err != nil {
+		return nil, err
+	} else if substitutedLower, err := integral.lower.Substitute(substitutions); err != nil {
+		return nil, err
+	} else if substitutedUpper, err := integral.upper.Substitute(substitutions); err != nil {
+		return nil, err
+	} else {
+		return integral.rebuild(substitutedIntegrand, substitutedLower, substitutedUpper), nil
+	}
+}
+
+
+// Creates a new integral expression with the same integration
+// variable and algorithm, but the given integrand and bounds.
+func (integral IntegralExpr) rebuild(integrand, lower, upper Expression) Expression {
+	return IntegralExpr{integrand, integral.wrt, lower, upper, integral.factory, integral.factoryName}
+}
+
+
+// Gets all the arguments, but the one for the integration variable.
+func (integral IntegralExpr) getNonBoundArguments(arguments Arguments) Arguments {
+	argumentsCopy := Arguments{}
+	for key, value := range arguments {
+		if key != integral.wrt {
+			argumentsCopy[key] = value
+		}
+	}
+	return argumentsCopy
+}
+
+
+// Evaluate computes the bounds, and then estimates the integral of the curried integrand.
+func (integral IntegralExpr) Evaluate(args Arguments) (sets.Number, error) {
+	if lower, err := integral.lower.Evaluate(args); err != nil {
+		return nil, err
+	} else if upper, err := integral.upper.Evaluate(args); err != nil {
+		return nil, err
+	} else if engine, err := integral.factory(args, integral.wrt); err != nil {
+		return nil, err
+	} else if curried, err := integral.integrand.Curry(integral.getNonBoundArguments(args)); err != nil {
+		return nil, err
+	} else {
+		return engine.Integrate(curried, lower, upper)
+	}
+}
+
+
+func (integral IntegralExpr) String() string {
+	return fmt.Sprintf("Integral({%s d%s, %s .. %s})", integral.integrand, integral.wrt, integral.lower, integral.upper)
+}
+
+
+func (integral IntegralExpr) IsSelfContained() bool {
+	return true
+}
+
+
+// Integral constructs a definite integral expression, computed by the algorithms
+// the given factory builds.
+func Integral(integrand Expression, wrt Variable, lower, upper Expression, factory IntegrationAlgorithmFactory) Expression {
+	return IntegralExpr{integrand, wrt, lower, upper, factory, ""}
+}
+
+
+var integrationAlgorithmFactories = map[string]IntegrationAlgorithmFactory{}
+
+
+// RegisterIntegrationAlgorithmFactory registers an algorithm factory under a
+// name, so integral expressions can refer to it by name (e.g. when they are
+// encoded). Registering an already-registered name replaces the former factory.
+func RegisterIntegrationAlgorithmFactory(name string, factory IntegrationAlgorithmFactory) {
+	integrationAlgorithmFactories[name] = factory
+}
+
+
+// LookupIntegrationAlgorithmFactory returns the factory registered for the given name.
+func LookupIntegrationAlgorithmFactory(name string) (IntegrationAlgorithmFactory, bool) {
+	factory, ok := integrationAlgorithmFactories[name]
+	return factory, ok
+}
+
+
+// NamedIntegral constructs a definite integral expression using a registered algorithm
+// factory. Unlike the ones created by Integral, these expressions can be encoded.
+func NamedIntegral(integrand Expression, wrt Variable, lower, upper Expression, factoryName string) (Expression, error) {
+	if factory, ok := LookupIntegrationAlgorithmFactory(factoryName); !ok {
+		return nil, errors.ErrUnregisteredIntegrationAlgorithm
+	} else {
+		return IntegralExpr{integrand, wrt, lower, upper, factory, factoryName}, nil
+	}
+}
@@ -0,0 +1,267 @@
+package expressions
+
+import (
+	"github.com/universe-10th/calculus/errors"
+	"github.com/universe-10th/calculus/ops"
+)
+
+
+// Integrable is implemented by custom expressions knowing their own antiderivative.
+// Integrate(...) will use it for those expressions.
+type Integrable interface {
+	Expression
+	Integrate(wrt Variable) (Expression, error)
+}
+
+
+// How many nested integrations by parts will be attempted. Each one
+// typically reduces the degree of a polynomial factor by one.
+const maxIntegrationByPartsDepth = 16
+
+
+// linearSlope tells the slope of an expression, if it is linear (i.e. a*X + b,
+// where a and b are constant) on the given variable.
+func linearSlope(expression Expression, wrt Variable) (Expression, bool) {
+	if expression.IsConstant(wrt) {
+		return nil, false
+	} else if derivative, err := expression.Derivative(wrt); err != nil || !derivative.IsConstant(wrt) {
+		return nil, false
+	} else {
+		return derivative, true
+	}
+}
+
+
+// substituted integrates f(a*X + b) as F(a*X + b) / a, given the antiderivative F
+// of f. This is the linear substitution rule.
+func substituted(argument Expression, wrt Variable, antiderivative func(Expression) Expression) (Expression, error) {
+	if slope, ok := linearSlope(argument, wrt); !ok {
+		return nil, errors.ErrNotIntegrable
+	} else {
+		return Div(antiderivative(argument), slope), nil
+	}
+}
+
+
+func isMinusOne(expression Expression) bool {
+	constant, ok := expression.(Constant)
+	return ok && ops.IsOne(ops.Neg(constant.number))
+}
+
+
+func isOne(expression Expression) bool {
+	constant, ok := expression.(Constant)
+	return ok && ops.IsOne(constant.number)
+}
+
+
+// integratePower integrates (a*X + b)^n, for constant n, and c^(a*X + b), for constant c.
+// When c is 1, ln(c) is 0, so the power is integrated as the constant 1 instead.
+func integratePower(base, exponent Expression, wrt Variable) (Expression, error) {
+	if exponent.IsConstant(wrt) {
+		if simplified, err := exponent.Simplify(); err != nil {
+			return nil, err
+		} else if isMinusOne(simplified) {
+			return substituted(base, wrt, func(argument Expression) Expression {
+				return Ln(argument)
+			})
+		}
+		return substituted(base, wrt, func(argument Expression) Expression {
+			raised := Add(exponent, Num(1))
+			return Div(Pow(argument, raised), raised)
+		})
+	} else if base.IsConstant(wrt) {
+		if simplified, err := base.Simplify(); err != nil {
+			return nil, err
+		} else if isOne(simplified) {
+			return wrt, nil
+		}
+		return substituted(exponent, wrt, func(argument Expression) Expression {
+			return Div(Pow(base, argument), Ln(base))
+		})
+	}
+	return nil, errors.ErrNotIntegrable
+}
+
+
+// integrationByPartsCandidates tells the factors that will be tried as the "u"
+// part: logarithms first, and then polynomials. Other factors are not tried,
+// since their derivatives would not make the remaining integral simpler.
+func integrationByPartsCandidates(factors []Expression, wrt Variable) []int {
+	logarithms := []int{}
+	polynomials := []int{}
+	for index, factor := range factors {
+		switch factor.(type) {
+		case LnExpr, LogExpr:
+			logarithms = append(logarithms, index)
+		default:
+			if _, err := PolynomialCoefficients(factor, wrt); err == nil {
+				polynomials = append(polynomials, index)
+			}
+		}
+	}
+	return append(logarithms, polynomials...)
+}
+
+
+// integrateByParts applies the rule: integral(u * dv) = u * v - integral(v * du),
+// trying each factor as the u part.
+func integrateByParts(factors []Expression, wrt Variable, depth int) (Expression, error) {
+	if depth >= maxIntegrationByPartsDepth {
+		return nil, errors.ErrNotIntegrable
+	}
+	for _, index := range integrationByPartsCandidates(factors, wrt) {
+		u := factors[index]
+		rest := []Expression{}
+		rest = append(rest, factors[0:index]...)
+		rest = append(rest, factors[index + 1:]...)
+		if v, err := integrate(Mul(rest...), wrt, depth + 1); err != nil {
+			continue
+		} else if du, err := u.Derivative(wrt); err != nil {
+			continue
+		} else if remaining, err := Mul(v, du).Simplify(); err != nil {
+			continue
+		} else if integral, err := integrate(remaining, wrt, depth + 1); err != nil {
+			continue
+		} else {
+			return Sub(Mul(u, v), integral), nil
+		}
+	}
+	return nil, errors.ErrNotIntegrable
+}
+
+
+// integrateExpanded integrates the expansion of an expression, only when it is
+// actually an addition (otherwise, expanding does not help).
+func integrateExpanded(expression Expression, wrt Variable, depth int) (Expression, error) {
+	if expanded, err := Expand(expression); err != nil {
+		return nil, err
+	} else if addition, ok := expanded.(AddExpr); !ok {
+		return nil, errors.ErrNotIntegrable
+	} else {
+		return integrate(addition, wrt, depth)
+	}
+}
+
+
+func integrateProduct(mul MulExpr, wrt Variable, depth int) (Expression, error) {
+	constants := []Expression{}
+	factors := []Expression{}
+	for _, factor := range mul.factors {
+		if factor.IsConstant(wrt) {
+			constants = append(constants, factor)
+		} else {
+			factors = append(factors, factor)
+		}
+	}
+	if len(constants) != 0 {
+		if integral, err := integrate(Mul(factors...), wrt, depth); err != nil {
+			return nil, err
+		} else {
+			return Mul(append(constants, integral)...), nil
+		}
+	} else if len(factors) == 1 {
+		return integrate(factors[0], wrt, depth)
+	}
+	// Merging the factors (e.g. X * X into X^2) may leave a single, known, one.
+	if simplified, err := simplifiedProduct(factors); err == nil && !equivalent(simplified, mul) {
+		if _, ok := simplified.(MulExpr); !ok {
+			return integrate(simplified, wrt, depth)
+		}
+	}
+	if integral, err := integrateExpanded(mul, wrt, depth); err == nil {
+		return integral, nil
+	}
+	return integrateByParts(factors, wrt, depth)
+}
+
+
+func integrate(expression Expression, wrt Variable, depth int) (Expression, error) {
+	if expression.IsConstant(wrt) {
+		return Mul(expression, wrt), nil
+	}
+	switch node := expression.(type) {
+	case Integrable:
+		return node.Integrate(wrt)
+	case Variable:
+		return integratePower(node, Num(1), wrt)
+	case AddExpr:
+		integrals := make([]Expression, len(node.terms))
+		for index, term := range node.terms {
+			if integral, err := integrate(term, wrt, depth); err != nil {
+				return nil, err
+			} else {
+				integrals[index] = integral
+			}
+		}
+		return Add(integrals...), nil
+	case NegatedExpr:
+		if integral, err := integrate(node.arg, wrt, depth); err != nil {
+			return nil, err
+		} else {
+			return Negated(integral), nil
+		}
+	case MulExpr:
+		return integrateProduct(node, wrt, depth)
+	case InverseExpr:
+		switch inner := node.arg.(type) {
+		case PowExpr:
+			return integrate(Pow(inner.base, Negated(inner.exponent)), wrt, depth)
+		case ExpExpr:
+			return integrate(Exp(Negated(inner.exponent)), wrt, depth)
+		default:
+			return integratePower(inner, Num(-1), wrt)
+		}
+	case PowExpr:
+		if integral, err := integratePower(node.base, node.exponent, wrt); err == nil {
+			return integral, nil
+		}
+		return integrateExpanded(node, wrt, depth)
+	case ExpExpr:
+		return substituted(node.exponent, wrt, Exp)
+	case LnExpr:
+		// integral(ln(X)) = X * ln(X) - X
+		return substituted(node.arg, wrt, func(argument Expression) Expression {
+			return Sub(Mul(argument, Ln(argument)), argument)
+		})
+	case LogExpr:
+		// log(b, X) = ln(X) / ln(b)
+		if !node.base.IsConstant(wrt) {
+			return nil, errors.ErrNotIntegrable
+		}
+		return substituted(node.power, wrt, func(argument Expression) Expression {
+			return Div(Sub(Mul(argument, Ln(argument)), argument), Ln(node.base))
+		})
+	case SinExpr:
+		return substituted(node.arg, wrt, func(argument Expression) Expression {
+			return Negated(Cos(argument))
+		})
+	case CosExpr:
+		return substituted(node.arg, wrt, Sin)
+	case TanExpr:
+		// integral(tan(X)) = -ln(cos(X))
+		return substituted(node.arg, wrt, func(argument Expression) Expression {
+			return Negated(Ln(Cos(argument)))
+		})
+	default:
+		return nil, errors.ErrNotIntegrable
+	}
+}
+
+
+// Integrate computes an antiderivative (with no integration constant) of the
+// expression, with respect to the given variable. It supports constants, sums
+// and constant multiples, powers of X (including 1/X, integrated as ln(X), so
+// the result is only valid for positive values), exp, ln, log (with constant
+// base), sin, cos and tan, all of them also on linear arguments (a*X + b), and
+// integration by parts for products like X * exp(X) or X * ln(X). Custom nodes
+// may implement Integrable. Otherwise, ErrNotIntegrable is returned. The result
+// is simplified, so calling Derivative on it gives the original expression back
+// (perhaps in another, equivalent, form).
+func Integrate(expression Expression, wrt Variable) (Expression, error) {
+	if integral, err := integrate(expression, wrt, 0); err != nil {
+		return nil, err
+	} else {
+		return integral.Simplify()
+	}
+}
@@ -0,0 +1,173 @@
+package expressions
+
+import (
+	"math/big"
+	"github.com/universe-10th/calculus/errors"
+	"github.com/universe-10th/calculus/intervals"
+)
+
+
+// IntervalArguments maps each variable to the interval of values it may take.
+type IntervalArguments map[Variable]intervals.Interval
+
+
+// pointArguments converts the interval arguments to regular arguments, telling
+// whether all the intervals (of the given variables) are single points.
+func (arguments IntervalArguments) pointArguments(variables Variables) (Arguments, bool) {
+	points := Arguments{}
+	for variable := range variables {
+		if interval, ok := arguments[variable]; !ok {
+			continue
+		} else if !interval.IsPoint() {
+			return nil, false
+		} else {
+			points[variable] = interval.Lower()
+		}
+	}
+	return points, true
+}
+
+
+// EvaluateInterval computes an interval enclosing all the values the expression
+// takes when its variables range over the given intervals (an input box). The
+// enclosure is guaranteed (bounds are rounded outward) but not necessarily tight:
+// a variable appearing several times is treated as several independent ones.
+//
+// Functions undefined over a whole region (logarithms of negative numbers, powers
+// of negative bases to non-integer exponents, factorials of non-natural numbers,
+// arcs and inverse hyperbolic functions out of their domains) fail if the interval
+// touches that region, and so do the expressions undefined on integers, if the
+// interval contains one. Functions undefined on isolated
+// points (inverses of intervals containing 0, tangents over intervals containing
+// a pole) yield unbounded intervals instead. Goal-seeks, integrals and custom
+// functions can only be evaluated when all their variables are single points.
+func EvaluateInterval(expression Expression, arguments IntervalArguments) (intervals.Interval, error) {
+	switch node := expression.(type) {
+	case Constant:
+		return intervals.Point(node.number), nil
+	case Variable:
+		if interval, ok := arguments[node]; !ok {
+			return intervals.Interval{}, errors.ErrUndefinedValue
+		} else {
+			return interval, nil
+		}
+	}
+	if !isElementary(expression) {
+		variables := Variables{}
+		expression.CollectVariables(variables)
+		if points, ok := arguments.pointArguments(variables); !ok {
+			return intervals.Interval{}, errors.ErrUnsupportedIntervalExpression
+		} else if value, err := expression.Evaluate(points); err != nil {
+			return intervals.Interval{}, err
+		} else {
+			return intervals.Point(value), nil
+		}
+	}
+	inner := nodeArguments(expression)
+	values := make([]intervals.Interval, len(inner))
+	for index, argument := range inner {
+		if value, err := EvaluateInterval(argument, arguments); err != nil {
+			return intervals.Interval{}, err
+		} else {
+			values[index] = value
+		}
+	}
+	switch node := expression.(type) {
+	case AddExpr:
+		result := values[0]
+		for _, value := range values[1:] {
+			result = intervals.Add(result, value)
+		}
+		return result, nil
+	case MulExpr:
+		result := values[0]
+		for _, value := range values[1:] {
+			result = intervals.Mul(result, value)
+		}
+		return result, nil
+	case NegatedExpr:
+		return intervals.Neg(values[0]), nil
+	case InverseExpr:
+		return intervals.Inv(values[0])
+	case PowExpr:
+		return intervals.Pow(values[0], values[1])
+	case LnExpr:
+		return intervals.Ln(values[0])
+	case LogExpr:
+		return intervals.Log(values[1], values[0])
+	case ExpExpr:
+		return intervals.Exp(values[0]), nil
+	case SinExpr:
+		return intervals.Sin(values[0]), nil
+	case CosExpr:
+		return intervals.Cos(values[0]), nil
+	case TanExpr:
+		return intervals.Tan(values[0]), nil
+	case SecExpr:
+		return intervals.Inv(intervals.Cos(values[0]))
+	case CscExpr:
+		return intervals.Inv(intervals.Sin(values[0]))
+	case CotExpr:
+		return intervals.Div(intervals.Cos(values[0]), intervals.Sin(values[0]))
+	case ArcSinExpr:
+		return intervals.ArcSin(values[0])
+	case ArcCosExpr:
+		return intervals.ArcCos(values[0])
+	case ArcTanExpr:
+		return intervals.ArcTan(values[0]), nil
+	case ArcTan2Expr:
+		return intervals.ArcTan2(values[0], values[1])
+	case SinhExpr:
+		return intervals.Sinh(values[0]), nil
+	case CoshExpr:
+		return intervals.Cosh(values[0]), nil
+	case TanhExpr:
+		return intervals.Tanh(values[0]), nil
+	case ArcSinhExpr:
+		return intervals.ArcSinh(values[0]), nil
+	case ArcCoshExpr:
+		return intervals.ArcCosh(values[0])
+	case ArcTanhExpr:
+		return intervals.ArcTanh(values[0])
+	case FactorialExpr:
+		return intervals.Factorial(values[0])
+	case Round:
+		return intervals.Round(values[0], node.roundType), nil
+	case Frac:
+		return intervals.Frac(values[0]), nil
+	case AbsExpr:
+		return intervals.Abs(values[0]), nil
+	case SignExpr:
+		return intervals.Sign(values[0]), nil
+	case DefectiveOnInt:
+		if intervals.ContainsInteger(values[0]) {
+			return intervals.Interval{}, errors.ErrUndefinedOnInteger
+		}
+		return intervals.Point(node.result), nil
+	case DefectiveOnZero:
+		if values[0].Contains(big.NewInt(0)) {
+			return intervals.Interval{}, errors.ErrUndefinedOnZero
+		}
+		return intervals.Point(node.result), nil
+	default:
+		return intervals.Interval{}, errors.ErrUnsupportedIntervalExpression
+	}
+}
+
+
+// Bounds is a convenience function to evaluate the expression over intervals
+// given by their bounds: each variable is mapped to a pair of numbers (either
+// big numbers or primitive values).
+// It is an error if a lower bound is greater than its upper bound.
+func Bounds(expression Expression, bounds map[Variable][2]interface{}) (intervals.Interval, error) {
+	arguments := IntervalArguments{}
+	for variable, pair := range bounds {
+		if interval, err := intervals.New(pair[0], pair[1]); err != nil {
+			return intervals.Interval{}, err
+		} else {
+			arguments[variable] = interval
+		}
+	}
+	return EvaluateInterval(expression, arguments)
+}
+
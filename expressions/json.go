@@ -0,0 +1,349 @@
+package expressions
+
+import (
+	"encoding/json"
+	"math/big"
+	"github.com/universe-10th/calculus/errors"
+	"github.com/universe-10th/calculus/ops"
+	"github.com/universe-10th/calculus/sets"
+)
+
+
+// SchemaVersion is the version of the JSON schema expressions are encoded with.
+// Documents having a different version will be rejected when decoding.
+const SchemaVersion = 1
+
+
+// The document wraps the root node, telling the schema version.
+type jsonDocument struct {
+	Version    int       `json:"version"`
+	Expression *jsonNode `json:"expression"`
+}
+
+
+// A node tells its kind and, depending on it, some of the other fields:
+// - "constant": type ("int", "rat", "float"), value (exact text) and, for floats, precision.
+// - "variable": name.
+// - "add", "mul", "negated", "inverse", "pow", "factorial", "frac": arguments.
+// - "function": name (the registered one) and arguments.
+// - "round": roundType and arguments (a single one).
+// - "doi", "doz": result (a constant node) and arguments (a single one).
+// - "goal-seek": name (the registered factory), variable (the inverted one) and arguments (goal, target).
+// - "integral": name (the registered factory), variable (the integration one) and arguments (integrand, lower, upper).
+type jsonNode struct {
+	Kind      string      `json:"kind"`
+	Name      string      `json:"name,omitempty"`
+	Type      string      `json:"type,omitempty"`
+	Value     string      `json:"value,omitempty"`
+	Precision uint        `json:"precision,omitempty"`
+	RoundType *int        `json:"roundType,omitempty"`
+	Variable  string      `json:"variable,omitempty"`
+	Result    *jsonNode   `json:"result,omitempty"`
+	Arguments []*jsonNode `json:"arguments,omitempty"`
+}
+
+
+func encodeNumber(number sets.Number) (*jsonNode, error) {
+	switch value := number.(type) {
+	case *big.Int:
+		return &jsonNode{Kind: "constant", Type: "int", Value: value.String()}, nil
+	case *big.Rat:
+		return &jsonNode{Kind: "constant", Type: "rat", Value: value.String()}, nil
+	case *big.Float:
+		// The shortest text that parses back, at the same precision, to the same value.
+		return &jsonNode{Kind: "constant", Type: "float", Value: value.Text('g', -1), Precision: value.Prec()}, nil
+	default:
+		return nil, errors.ErrUnsupportedExpression
+	}
+}
+
+
+func encodeNodes(expressions []Expression) ([]*jsonNode, error) {
+	nodes := make([]*jsonNode, len(expressions))
+	for index, expression := range expressions {
+		if node, err := encodeNode(expression); err != nil {
+			return nil, err
+		} else {
+			nodes[index] = node
+		}
+	}
+	return nodes, nil
+}
+
+
+func encodeCompound(kind string, arguments ...Expression) (*jsonNode, error) {
+	if nodes, err := encodeNodes(arguments); err != nil {
+		return nil, err
+	} else {
+		return &jsonNode{Kind: kind, Arguments: nodes}, nil
+	}
+}
+
+
+func encodeNode(expression Expression) (*jsonNode, error) {
+	switch node := expression.(type) {
+	case Constant:
+		return encodeNumber(node.number)
+	case Variable:
+		return &jsonNode{Kind: "variable", Name: node.name}, nil
+	case AddExpr:
+		return encodeCompound("add", node.terms...)
+	case MulExpr:
+		return encodeCompound("mul", node.factors...)
+	case NegatedExpr:
+		return encodeCompound("negated", node.arg)
+	case InverseExpr:
+		return encodeCompound("inverse", node.arg)
+	case PowExpr:
+		return encodeCompound("pow", node.base, node.exponent)
+	case FactorialExpr:
+		return encodeCompound("factorial", node.arg)
+	case Frac:
+		return encodeCompound("frac", node.arg)
+	case Round:
+		if encoded, err := encodeCompound("round", node.arg); err != nil {
+			return nil, err
+		} else {
+			roundType := int(node.roundType)
+			encoded.RoundType = &roundType
+			return encoded, nil
+		}
+	case DefectiveOnInt:
+		if encoded, err := encodeCompound("doi", node.bypassed); err != nil {
+			return nil, err
+		} else if result, err := encodeNumber(node.result); err != nil {
+			return nil, err
+		} else {
+			encoded.Result = result
+			return encoded, nil
+		}
+	case DefectiveOnZero:
+		if encoded, err := encodeCompound("doz", node.bypassed); err != nil {
+			return nil, err
+		} else if result, err := encodeNumber(node.result); err != nil {
+			return nil, err
+		} else {
+			encoded.Result = result
+			return encoded, nil
+		}
+	case GoalSeekExpr:
+		if node.factoryName == "" {
+			return nil, errors.ErrUnregisteredGoalSeekingAlgorithm
+		} else if encoded, err := encodeCompound("goal-seek", node.goal, node.target); err != nil {
+			return nil, err
+		} else {
+			encoded.Name = node.factoryName
+			encoded.Variable = node.inverted.name
+			return encoded, nil
+		}
+	case IntegralExpr:
+		if node.factoryName == "" {
+			return nil, errors.ErrUnregisteredIntegrationAlgorithm
+		} else if encoded, err := encodeCompound("integral", node.integrand, node.lower, node.upper); err != nil {
+			return nil, err
+		} else {
+			encoded.Name = node.factoryName
+			encoded.Variable = node.wrt.name
+			return encoded, nil
+		}
+	case Function:
+		// Built-in functions (ln, log, exp, sin, cos, tan) and custom
+		// ones are encoded by name: it must be a registered one.
+		if encoded, err := encodeCompound("function", node.Arguments()...); err != nil {
+			return nil, err
+		} else {
+			encoded.Name = node.StandardName()
+			return encoded, nil
+		}
+	default:
+		return nil, errors.ErrUnsupportedExpression
+	}
+}
+
+
+func decodeNumber(node *jsonNode) (sets.Number, error) {
+	if node == nil || node.Kind != "constant" {
+		return nil, errors.ErrMalformedEncodedExpression
+	}
+	switch node.Type {
+	case "int":
+		if value, ok := big.NewInt(0).SetString(node.Value, 10); ok {
+			return value, nil
+		}
+	case "rat":
+		if value, ok := big.NewRat(0, 1).SetString(node.Value); ok {
+			return value, nil
+		}
+	case "float":
+		if node.Precision == 0 || node.Precision > big.MaxPrec {
+			return nil, errors.ErrMalformedEncodedExpression
+		}
+		if value, _, err := big.ParseFloat(node.Value, 10, node.Precision, big.ToNearestEven); err == nil {
+			return value, nil
+		}
+	}
+	return nil, errors.ErrMalformedEncodedExpression
+}
+
+
+func decodeArguments(node *jsonNode, count int) ([]Expression, error) {
+	if count >= 0 && len(node.Arguments) != count {
+		return nil, errors.ErrMalformedEncodedExpression
+	}
+	arguments := make([]Expression, len(node.Arguments))
+	for index, argument := range node.Arguments {
+		if decoded, err := decodeNode(argument); err != nil {
+			return nil, err
+		} else {
+			arguments[index] = decoded
+		}
+	}
+	return arguments, nil
+}
+
+
+func decodeNode(node *jsonNode) (Expression, error) {
+	if node == nil {
+		return nil, errors.ErrMalformedEncodedExpression
+	}
+	var count int
+	switch node.Kind {
+	case "constant":
+		if number, err := decodeNumber(node); err != nil {
+			return nil, err
+		} else {
+			return Constant{number}, nil
+		}
+	case "variable":
+		if node.Name == "" {
+			return nil, errors.ErrMalformedEncodedExpression
+		}
+		return Var(node.Name), nil
+	case "add", "mul", "function":
+		count = -1
+	case "negated", "inverse", "factorial", "frac", "round", "doi", "doz":
+		count = 1
+	case "pow", "goal-seek":
+		count = 2
+	case "integral":
+		count = 3
+	default:
+		return nil, errors.ErrMalformedEncodedExpression
+	}
+
+	arguments, err := decodeArguments(node, count)
+	if err != nil {
+		return nil, err
+	}
+	switch node.Kind {
+	case "add":
+		if len(arguments) == 0 {
+			return nil, errors.ErrMalformedEncodedExpression
+		}
+		return Add(arguments...), nil
+	case "mul":
+		if len(arguments) == 0 {
+			return nil, errors.ErrMalformedEncodedExpression
+		}
+		return Mul(arguments...), nil
+	case "negated":
+		return NegatedExpr{arguments[0]}, nil
+	case "inverse":
+		return InverseExpr{arguments[0]}, nil
+	case "pow":
+		return Pow(arguments[0], arguments[1]), nil
+	case "factorial":
+		return Factorial(arguments[0]), nil
+	case "frac":
+		return Frac{arguments[0]}, nil
+	case "round":
+		if node.RoundType == nil {
+			return nil, errors.ErrMalformedEncodedExpression
+		} else if roundType := ops.RoundType(*node.RoundType); roundType < ops.Ceil || roundType > ops.Outward {
+			return nil, errors.ErrMalformedEncodedExpression
+		} else {
+			return Round{arguments[0], roundType}, nil
+		}
+	case "doi":
+		if result, err := decodeNumber(node.Result); err != nil {
+			return nil, err
+		} else {
+			return DefectiveOnInt{arguments[0], result}, nil
+		}
+	case "doz":
+		if result, err := decodeNumber(node.Result); err != nil {
+			return nil, err
+		} else {
+			return DefectiveOnZero{arguments[0], result}, nil
+		}
+	case "goal-seek":
+		if node.Variable == "" {
+			return nil, errors.ErrMalformedEncodedExpression
+		}
+		return NamedGoalSeek(arguments[0], arguments[1], Var(node.Variable), node.Name)
+	case "integral":
+		if node.Variable == "" {
+			return nil, errors.ErrMalformedEncodedExpression
+		}
+		return NamedIntegral(arguments[0], Var(node.Variable), arguments[1], arguments[2], node.Name)
+	default:
+		if builder, ok := LookupFunction(node.Name); !ok {
+			return nil, errors.ErrUnregisteredFunction
+		} else {
+			return builder(arguments...)
+		}
+	}
+}
+
+
+// Marshal encodes an expression as a JSON document, according to the current
+// SchemaVersion. Custom functions are encoded by their standard name, and goal
+// seeks and integrals by the name of their algorithm factory: only goal-seeks
+// created by NamedGoalSeek (and integrals created by NamedIntegral) can be encoded.
+func Marshal(expression Expression) ([]byte, error) {
+	if node, err := encodeNode(expression); err != nil {
+		return nil, err
+	} else {
+		return json.Marshal(jsonDocument{SchemaVersion, node})
+	}
+}
+
+
+// Unmarshal decodes an expression out of a JSON document. The functions, goal
+// seeking algorithm factories and integration algorithm factories mentioned in
+// the document must be registered (via RegisterFunction, RegisterGoalSeekingAlgorithmFactory
+// and RegisterIntegrationAlgorithmFactory respectively).
+func Unmarshal(data []byte) (Expression, error) {
+	document := jsonDocument{}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, err
+	} else if document.Version != SchemaVersion {
+		return nil, errors.ErrUnsupportedSchemaVersion
+	} else {
+		return decodeNode(document.Expression)
+	}
+}
+
+
+// JSONExpression wraps an expression so it can be embedded in structures
+// being encoded by the encoding/json package.
+type JSONExpression struct {
+	Expression
+}
+
+
+// MarshalJSON encodes the wrapped expression.
+func (jsonExpression JSONExpression) MarshalJSON() ([]byte, error) {
+	return Marshal(jsonExpression.Expression)
+}
+
+
+// UnmarshalJSON decodes an expression and wraps it.
+func (jsonExpression *JSONExpression) UnmarshalJSON(data []byte) error {
+	if expression, err := Unmarshal(data); err != nil {
+		return err
+	} else {
+		jsonExpression.Expression = expression
+		return nil
+	}
+}
@@ -0,0 +1,307 @@
+package expressions
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"github.com/universe-10th/calculus/ops"
+	"github.com/universe-10th/calculus/sets"
+)
+
+
+// LaTeXRenderer is implemented by expressions knowing how to render themselves
+// as LaTeX. Custom expressions may implement it to be supported by LaTeX(...).
+type LaTeXRenderer interface {
+	Expression
+	LaTeX() string
+}
+
+
+// The LaTeX commands of functions being rendered as \command\left(args\right).
+// Functions not listed here are rendered as \operatorname{name}\left(args\right).
+var latexFunctionCommands = map[string]string{
+	"ln":  `\ln`,
+	"sin": `\sin`,
+	"cos": `\cos`,
+	"tan": `\tan`,
+	"sec": `\sec`,
+	"csc": `\csc`,
+	"cot": `\cot`,
+	"arcsin": `\arcsin`,
+	"arccos": `\arccos`,
+	"arctan": `\arctan`,
+	"sinh": `\sinh`,
+	"cosh": `\cosh`,
+	"tanh": `\tanh`,
+	"arcsinh": `\operatorname{arsinh}`,
+	"arccosh": `\operatorname{arcosh}`,
+	"arctanh": `\operatorname{artanh}`,
+	"min": `\min`,
+	"max": `\max`,
+	"sign": `\operatorname{sgn}`,
+}
+
+
+func latexEscape(text string) string {
+	return strings.NewReplacer(`\`, `\backslash `, "_", `\_`, "{", `\{`, "}", `\}`, "#", `\#`,
+		"$", `\$`, "%", `\%`, "&", `\&`, "^", `\^{}`, "~", `\~{}`).Replace(text)
+}
+
+
+func latexParentheses(content string) string {
+	return `\left(` + content + `\right)`
+}
+
+
+// latexOperand renders an expression being an operand of a higher-precedence
+// operator, wrapping it in parentheses unless it is self-contained.
+func latexOperand(expression Expression) string {
+	if isSelfContained(expression) {
+		return LaTeX(expression)
+	}
+	return latexParentheses(LaTeX(expression))
+}
+
+
+// latexArgument renders an expression being the argument of a function. Only
+// variables and non-negative constants are rendered without parentheses (but
+// separated from the function command by a space).
+func latexArgument(expression Expression) string {
+	switch node := expression.(type) {
+	case Variable:
+		return " " + LaTeX(node)
+	case Constant:
+		if !ops.IsNegative(node.number) {
+			if _, ok := node.number.(*big.Rat); !ok {
+				return " " + LaTeX(node)
+			}
+		}
+	}
+	return latexParentheses(LaTeX(expression))
+}
+
+
+func latexNumber(number sets.Number) string {
+	switch value := number.(type) {
+	case *big.Int:
+		return value.String()
+	case *big.Rat:
+		if value.IsInt() {
+			return value.Num().String()
+		} else if value.Sign() < 0 {
+			return fmt.Sprintf(`-\frac{%s}{%s}`, big.NewInt(0).Neg(value.Num()), value.Denom())
+		} else {
+			return fmt.Sprintf(`\frac{%s}{%s}`, value.Num(), value.Denom())
+		}
+	case *big.Float:
+		if value.IsInf() {
+			if value.Sign() < 0 {
+				return `-\infty`
+			}
+			return `\infty`
+		}
+		return value.Text('f', -1)
+	}
+	return "?"
+}
+
+
+func latexVariable(variable Variable) string {
+	if len([]rune(variable.name)) == 1 {
+		return variable.name
+	}
+	return `\mathrm{` + latexEscape(variable.name) + `}`
+}
+
+
+func latexAdd(add AddExpr) string {
+	builder := strings.Builder{}
+	for index, term := range add.terms {
+		negative := false
+		var rendered string
+		switch node := term.(type) {
+		case NegatedExpr:
+			negative = true
+			if _, ok := node.arg.(AddExpr); ok {
+				rendered = latexParentheses(LaTeX(node.arg))
+			} else {
+				rendered = LaTeX(node.arg)
+			}
+		case Constant:
+			if ops.IsNegative(node.number) {
+				negative = true
+				rendered = latexNumber(ops.Neg(node.number))
+			} else {
+				rendered = latexNumber(node.number)
+			}
+		default:
+			rendered = LaTeX(term)
+		}
+		if index == 0 {
+			if negative {
+				builder.WriteString("-")
+			}
+		} else if negative {
+			builder.WriteString(" - ")
+		} else {
+			builder.WriteString(" + ")
+		}
+		builder.WriteString(rendered)
+	}
+	return builder.String()
+}
+
+
+func latexFactors(factors []Expression) string {
+	if len(factors) == 0 {
+		return "1"
+	} else if len(factors) == 1 {
+		return LaTeX(factors[0])
+	}
+	rendered := make([]string, len(factors))
+	for index, factor := range factors {
+		switch node := factor.(type) {
+		case AddExpr, NegatedExpr:
+			rendered[index] = latexParentheses(LaTeX(node))
+		case Constant:
+			if index > 0 && ops.IsNegative(node.number) {
+				rendered[index] = latexParentheses(LaTeX(node))
+			} else {
+				rendered[index] = LaTeX(node)
+			}
+		default:
+			rendered[index] = LaTeX(node)
+		}
+	}
+	return strings.Join(rendered, ` \cdot `)
+}
+
+
+// Products are rendered as fractions: their inverted factors become the denominator.
+func latexMul(mul MulExpr) string {
+	numerator := []Expression{}
+	denominator := []Expression{}
+	for _, factor := range mul.factors {
+		if inverse, ok := factor.(InverseExpr); ok {
+			denominator = append(denominator, inverse.arg)
+		} else {
+			numerator = append(numerator, factor)
+		}
+	}
+	if len(denominator) == 0 {
+		return latexFactors(numerator)
+	}
+	return fmt.Sprintf(`\frac{%s}{%s}`, latexFactors(numerator), latexFactors(denominator))
+}
+
+
+// Bases are wrapped unless self-contained. Also, exponentials (to avoid a double
+// superscript), fractional constants and functions (but absolute values and
+// Iverson brackets) are wrapped.
+func latexPow(pow PowExpr) string {
+	base := latexOperand(pow.base)
+	switch node := pow.base.(type) {
+	case ExpExpr, DefectiveOnInt, DefectiveOnZero:
+		base = latexParentheses(base)
+	case Constant:
+		if value, ok := node.number.(*big.Rat); ok && !value.IsInt() && value.Sign() > 0 {
+			base = latexParentheses(base)
+		}
+	case AbsExpr, ComparisonExpr:
+		// They are already delimited by bars or brackets.
+	case Function:
+		// Otherwise, \sin X^{2} would be read as sin(X^2).
+		base = latexParentheses(base)
+	}
+	return fmt.Sprintf(`%s^{%s}`, base, LaTeX(pow.exponent))
+}
+
+
+func latexRound(round Round) string {
+	argument := LaTeX(round.arg)
+	switch round.roundType {
+	case ops.Ceil:
+		return `\left\lceil ` + argument + ` \right\rceil`
+	case ops.Floor:
+		return `\left\lfloor ` + argument + ` \right\rfloor`
+	case ops.Inward:
+		return `\operatorname{round}_{\to 0}` + latexParentheses(argument)
+	default:
+		return `\operatorname{round}_{\to \pm\infty}` + latexParentheses(argument)
+	}
+}
+
+
+func latexFunction(function Function) string {
+	arguments := function.Arguments()
+	name := function.StandardName()
+	command, ok := latexFunctionCommands[strings.ToLower(name)]
+	if !ok {
+		command = `\operatorname{` + latexEscape(name) + `}`
+	}
+	if len(arguments) == 1 {
+		return command + latexArgument(arguments[0])
+	}
+	rendered := make([]string, len(arguments))
+	for index, argument := range arguments {
+		rendered[index] = LaTeX(argument)
+	}
+	return command + latexParentheses(strings.Join(rendered, ", "))
+}
+
+
+// LaTeX renders an expression as LaTeX (math mode) source. Inverted factors in
+// products are rendered as fractions, powers as superscripts, and functions by
+// their usual commands (\ln, \log_b, \sin, ...). Parentheses are only added when
+// needed. Custom expressions may implement LaTeXRenderer to be rendered, while
+// custom functions are rendered as \operatorname{name}(args).
+func LaTeX(expression Expression) string {
+	switch node := expression.(type) {
+	case LaTeXRenderer:
+		return node.LaTeX()
+	case Constant:
+		return latexNumber(node.number)
+	case Variable:
+		return latexVariable(node)
+	case AddExpr:
+		return latexAdd(node)
+	case MulExpr:
+		return latexMul(node)
+	case NegatedExpr:
+		if _, ok := node.arg.(AddExpr); ok {
+			return "-" + latexParentheses(LaTeX(node.arg))
+		}
+		return "-" + LaTeX(node.arg)
+	case InverseExpr:
+		return fmt.Sprintf(`\frac{1}{%s}`, LaTeX(node.arg))
+	case PowExpr:
+		return latexPow(node)
+	case ExpExpr:
+		return fmt.Sprintf(`e^{%s}`, LaTeX(node.exponent))
+	case LogExpr:
+		return fmt.Sprintf(`\log_{%s}%s`, LaTeX(node.base), latexArgument(node.power))
+	case FactorialExpr:
+		if _, ok := node.arg.(FactorialExpr); ok || !isSelfContained(node.arg) {
+			return latexParentheses(LaTeX(node.arg)) + "!"
+		}
+		return LaTeX(node.arg) + "!"
+	case Round:
+		return latexRound(node)
+	case Frac:
+		return `\operatorname{frac}` + latexParentheses(LaTeX(node.arg))
+	case DefectiveOnInt:
+		return fmt.Sprintf(`\left. %s \right|_{%s \notin \mathbb{Z}}`, latexNumber(node.result), LaTeX(node.bypassed))
+	case DefectiveOnZero:
+		return fmt.Sprintf(`\left. %s \right|_{%s \neq 0}`, latexNumber(node.result), LaTeX(node.bypassed))
+	case GoalSeekExpr:
+		return fmt.Sprintf(`\operatorname{solve}_{%s}\left(%s = %s\right)`,
+			LaTeX(node.inverted), LaTeX(node.target), LaTeX(node.goal))
+	case IntegralExpr:
+		return fmt.Sprintf(`\int_{%s}^{%s} %s \, d%s`,
+			LaTeX(node.lower), LaTeX(node.upper), LaTeX(node.integrand), LaTeX(node.wrt))
+	case Function:
+		return latexFunction(node)
+	default:
+		return `\text{` + latexEscape(expression.String()) + `}`
+	}
+}
@@ -3,6 +3,8 @@ package expressions
 import (
 	"github.com/universe-10th/calculus/sets"
 	"github.com/universe-10th/calculus/ops"
+	"github.com/universe-10th/calculus/errors"
+	"math/big"
 	"strings"
 )
 
@@ -30,6 +32,21 @@ func (mul MulExpr) Curry(args Arguments) (Expression, error) {
 }
 
 
+// Substitute replaces the variables in each factor independently, and then generates a new node.
+// Unlike Curry, it does not attempt simplifying the result.
+func (mul MulExpr) Substitute(substitutions Substitutions) (Expression, error) {
+	newFactors := make([]Expression, len(mul.factors))
+	for index, value := range mul.factors {
+		if substituted, err := value.Substitute(substitutions); err != nil {
+			return nil, err
+		} else {
+			newFactors[index] = substituted
+		}
+	}
+	return Mul(newFactors...), nil
+}
+
+
 // Evaluate evaluates the product of the evaluated factor's values.
 func (mul MulExpr) Evaluate(args Arguments) (sets.Number, error) {
 	factors := make([]sets.Number, len(mul.factors))
@@ -97,32 +114,155 @@ func (mul MulExpr) IsConstant(wrt Variable) bool {
 }
 
 
-// Simplify compresses all the constant factors into one single constant factor.
+// A power factor is a base, and all the exponents it was found with.
+type powerFactor struct {
+	base      Expression
+	exponents []Expression
+}
+
+
+// A factor collector gathers the numeric coefficients and groups the remaining
+// factors by their base.
+type factorCollector struct {
+	coefficients []sets.Number
+	powers       []*powerFactor
+}
+
+
+func (collector *factorCollector) addPower(base, exponent Expression) {
+	for _, power := range collector.powers {
+		if equivalent(power.base, base) {
+			power.exponents = append(power.exponents, exponent)
+			return
+		}
+	}
+	collector.powers = append(collector.powers, &powerFactor{base, []Expression{exponent}})
+}
+
+
+// collect adds a factor, which may be inverted (i.e. it is a divider). Negations
+// and constants become part of the coefficient, while products (typically inside
+// inverses) are collected factor by factor.
+func (collector *factorCollector) collect(factor Expression, inverted bool) error {
+	switch node := factor.(type) {
+	case Constant:
+		if !inverted {
+			collector.coefficients = append(collector.coefficients, node.number)
+		} else if ops.IsZero(node.number) {
+			return errors.ErrDivisionByZero
+		} else {
+			collector.coefficients = append(collector.coefficients, ops.Inv(node.number))
+		}
+	case NegatedExpr:
+		collector.coefficients = append(collector.coefficients, big.NewInt(-1))
+		return collector.collect(node.arg, inverted)
+	case MulExpr:
+		for _, inner := range node.factors {
+			if err := collector.collect(inner, inverted); err != nil {
+				return err
+			}
+		}
+	case InverseExpr:
+		return collector.collect(node.arg, !inverted)
+	case PowExpr:
+		// (X * Y)^n = X^n * Y^n only holds in general when n is integer.
+		if product, ok := node.base.(MulExpr); ok {
+			if constant, ok := node.exponent.(Constant); ok && sets.BelongsTo(constant.number, sets.Z) {
+				for _, inner := range product.factors {
+					if err := collector.collect(Pow(inner, constant), inverted); err != nil {
+						return err
+					}
+				}
+				return nil
+			}
+		}
+		if inverted {
+			collector.addPower(node.base, Negated(node.exponent))
+		} else {
+			collector.addPower(node.base, node.exponent)
+		}
+	default:
+		if inverted {
+			collector.addPower(factor, Num(-1))
+		} else {
+			collector.addPower(factor, Num(1))
+		}
+	}
+	return nil
+}
+
+
+// Simplify compresses all the constant factors into one single constant factor,
+// and merges the factors having the same base by adding their exponents: X * X
+// becomes X^2, and X * Y / X becomes Y. The first occurrence of a base tells the
+// place the merged factor will have, while the constant factor is placed first.
 // The result is returned as a new expressions rather than modifying the current one.
 // Note: this method is optimized: if at least one factor results in constant 0, the
 // constant 0 expression will be returned.
 func (mul MulExpr) Simplify() (Expression, error) {
-	simplifiedTerms := []sets.Number{}
-	nonSimplifiedTerms := []Expression{}
-
-	for _, factor := range mul.factors {
+	simplifiedFactors := make([]Expression, len(mul.factors))
+	for index, factor := range mul.factors {
 		if simplified, err := factor.Simplify(); err != nil {
 			return nil, err
-		} else if num, ok := simplified.(Constant); ok {
-			simplifiedTerms = append(simplifiedTerms, num.number)
 		} else {
-			nonSimplifiedTerms = append(nonSimplifiedTerms, simplified)
+			simplifiedFactors[index] = simplified
+		}
+	}
+	return simplifiedProduct(simplifiedFactors)
+}
+
+
+// simplifiedProduct does the actual simplification of the multiplication,
+// assuming the given factors are already simplified.
+func simplifiedProduct(factors []Expression) (Expression, error) {
+	collector := factorCollector{}
+	for _, factor := range factors {
+		if err := collector.collect(factor, false); err != nil {
+			return nil, err
 		}
 	}
 
-	simplifiedSummary := ops.Mul(simplifiedTerms...)
+	simplifiedSummary := ops.Mul(collector.coefficients...)
 	if simplifiedSummary != nil && ops.IsZero(simplifiedSummary) {
 		return Num(0), nil
 	}
 
+	nonSimplifiedTerms := []Expression{}
+	for _, power := range collector.powers {
+		exponent := simplifiedSum(power.exponents)
+		if constant, ok := exponent.(Constant); ok {
+			if ops.IsZero(constant.number) {
+				continue
+			} else if ops.IsOne(constant.number) {
+				nonSimplifiedTerms = append(nonSimplifiedTerms, power.base)
+				continue
+			} else if ops.IsOne(ops.Neg(constant.number)) {
+				nonSimplifiedTerms = append(nonSimplifiedTerms, Inverse(power.base))
+				continue
+			}
+		}
+		if simplified, err := simplifiedPower(power.base, exponent); err != nil {
+			return nil, err
+		} else if constant, ok := simplified.(Constant); ok {
+			if simplifiedSummary == nil {
+				simplifiedSummary = constant.number
+			} else {
+				simplifiedSummary = ops.Mul(simplifiedSummary, constant.number)
+			}
+		} else {
+			nonSimplifiedTerms = append(nonSimplifiedTerms, simplified)
+		}
+	}
+
+	if simplifiedSummary != nil {
+		simplifiedSummary = normalizedNumber(simplifiedSummary)
+	}
 	length := len(nonSimplifiedTerms)
 	if length != 0 {
-		if simplifiedSummary != nil && !ops.IsOne(simplifiedSummary) {
+		negate := false
+		if simplifiedSummary != nil && ops.IsOne(ops.Neg(simplifiedSummary)) {
+			negate = true
+		} else if simplifiedSummary != nil && !ops.IsOne(simplifiedSummary) {
 			finalTerms := make([]Expression, length + 1)
 			finalTerms[0] = Constant{simplifiedSummary}
 			for index, term := range nonSimplifiedTerms {
@@ -130,11 +270,16 @@ func (mul MulExpr) Simplify() (Expression, error) {
 			}
 			nonSimplifiedTerms = finalTerms
 		}
+		var result Expression
 		if len(nonSimplifiedTerms) == 1 {
-			return nonSimplifiedTerms[0], nil
+			result = nonSimplifiedTerms[0]
 		} else {
-			return Mul(nonSimplifiedTerms...), nil
+			result = Mul(nonSimplifiedTerms...)
+		}
+		if negate {
+			return Negated(result), nil
 		}
+		return result, nil
 	} else {
 		if simplifiedSummary != nil {
 			return Constant{simplifiedSummary}, nil
@@ -218,5 +363,8 @@ func Mul(factors ...Expression) Expression {
 
 // Div constructs a new dividing multiplication node given their factors.
 func Div(dividend Expression, dividers ...Expression) Expression {
+	if len(dividers) == 1 {
+		return Mul(dividend, Inverse(dividers[0]))
+	}
 	return Mul(dividend, Inverse(Mul(dividers...)))
 }
\ No newline at end of file
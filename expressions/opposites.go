@@ -24,6 +24,16 @@ func (negated NegatedExpr) Curry(args Arguments) (Expression, error) {
 }
 
 
+// Substitute replaces the variables in the underlying expression, and rebuilds the node.
+func (negated NegatedExpr) Substitute(substitutions Substitutions) (Expression, error) {
+	if substituted, err := negated.arg.Substitute(substitutions); err != nil {
+		return nil, err
+	} else {
+		return Negated(substituted), nil
+	}
+}
+
+
 // Evaluate computes the inner expression's evaluated value and negates it.
 func (negated NegatedExpr) Evaluate(args Arguments) (sets.Number, error) {
 	if result, err := negated.arg.Evaluate(args); err != nil {
@@ -69,10 +79,11 @@ func (negated NegatedExpr) Simplify() (Expression, error) {
 }
 
 
-// String represents the negation appropriately, as -X or -(X).
+// String represents the negation appropriately, as -X or -(X). Products are
+// wrapped as well, since -X * Y would be read as (-X) * Y.
 func (negated NegatedExpr) String() string {
 	switch v := negated.arg.(type) {
-	case AddExpr:
+	case AddExpr, MulExpr:
 		return fmt.Sprintf("-(%s)", v)
 	default:
 		return fmt.Sprintf("-%s", v)
@@ -108,6 +119,16 @@ func (inverse InverseExpr) Curry(args Arguments) (Expression, error) {
 }
 
 
+// Substitute replaces the variables in the underlying expression, and rebuilds the node.
+func (inverse InverseExpr) Substitute(substitutions Substitutions) (Expression, error) {
+	if substituted, err := inverse.arg.Substitute(substitutions); err != nil {
+		return nil, err
+	} else {
+		return Inverse(substituted), nil
+	}
+}
+
+
 // Evaluate computes the value of the inner expression, and then divides 1 by it.
 // It returns an error if a division-by-zero occurs.
 func (inverse InverseExpr) Evaluate(args Arguments) (sets.Number, error) {
@@ -124,7 +145,7 @@ func (inverse InverseExpr) Derivative(wrt Variable) (Expression, error) {
 	if derivative, err := inverse.arg.Derivative(wrt); err != nil {
 		return nil, err
 	} else {
-		if constant, ok := derivative.(Constant); !ok && ops.IsZero(constant.number) {
+		if constant, ok := derivative.(Constant); ok && ops.IsZero(constant.number) {
 			return Num(0), nil
 		} else {
 			return Negated(Mul(derivative, Pow(inverse.arg, Num(-2)))).Simplify()
@@ -177,7 +198,7 @@ func (inverse InverseExpr) Simplify() (Expression, error) {
 
 // String represents the inverse of a value as X^-1 or (X)^-1.
 func (inverse InverseExpr) String() string {
-	if _, ok := inverse.arg.(SelfContained); !ok {
+	if !isSelfContained(inverse.arg) {
 		return fmt.Sprintf("(%s)^-1", inverse.arg)
 	} else {
 		return fmt.Sprintf("%s^-1", inverse.arg)
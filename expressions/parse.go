@@ -0,0 +1,491 @@
+package expressions
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"unicode"
+	"github.com/universe-10th/calculus/ops"
+	"github.com/universe-10th/calculus/sets"
+)
+
+
+// ParseError is returned when a text cannot be parsed as an expression.
+// It tells the (1-based) line and column where the problem was found.
+type ParseError struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+
+// Error represents the parse error along with its position.
+func (parseError ParseError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s", parseError.Line, parseError.Column, parseError.Message)
+}
+
+
+type tokenKind int
+
+
+const (
+	endToken tokenKind = iota
+	intToken
+	ratToken
+	floatToken
+	identifierToken
+	operatorToken
+)
+
+
+// A token has its kind, its text, and the position it starts at.
+type token struct {
+	kind   tokenKind
+	text   string
+	line   int
+	column int
+}
+
+
+// String describes the token for error messages.
+func (token token) String() string {
+	switch token.kind {
+	case endToken:
+		return "end of input"
+	case identifierToken:
+		return fmt.Sprintf("identifier %q", token.text)
+	case operatorToken:
+		return fmt.Sprintf("%q", token.text)
+	default:
+		return fmt.Sprintf("number %s", token.text)
+	}
+}
+
+
+// The lexer splits the source in tokens, keeping track of lines and columns.
+type lexer struct {
+	source []rune
+	offset int
+	line   int
+	column int
+}
+
+
+func (lexer *lexer) peekRune(ahead int) rune {
+	if lexer.offset + ahead < len(lexer.source) {
+		return lexer.source[lexer.offset + ahead]
+	}
+	return 0
+}
+
+
+func (lexer *lexer) advance() rune {
+	current := lexer.source[lexer.offset]
+	lexer.offset++
+	if current == '\n' {
+		lexer.line++
+		lexer.column = 1
+	} else {
+		lexer.column++
+	}
+	return current
+}
+
+
+func (lexer *lexer) scanDigits(builder *strings.Builder) int {
+	count := 0
+	for unicode.IsDigit(lexer.peekRune(0)) {
+		builder.WriteRune(lexer.advance())
+		count++
+	}
+	return count
+}
+
+
+func isIdentifierStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+
+func isIdentifierPart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+
+// Numbers are integers (3), rationals (3/4, with no spaces around the slash,
+// which is the way *big.Rat values are represented) or decimals (3.75, 1e-3).
+func (lexer *lexer) scanNumber(current *token) error {
+	builder := strings.Builder{}
+	lexer.scanDigits(&builder)
+	current.kind = intToken
+	if lexer.peekRune(0) == '/' && unicode.IsDigit(lexer.peekRune(1)) {
+		builder.WriteRune(lexer.advance())
+		lexer.scanDigits(&builder)
+		current.kind = ratToken
+	} else {
+		if lexer.peekRune(0) == '.' {
+			builder.WriteRune(lexer.advance())
+			if lexer.scanDigits(&builder) == 0 {
+				return ParseError{lexer.line, lexer.column, "expected digits after the decimal point"}
+			}
+			current.kind = floatToken
+		}
+		if next := lexer.peekRune(0); next == 'e' || next == 'E' {
+			sign := lexer.peekRune(1)
+			if unicode.IsDigit(sign) || ((sign == '+' || sign == '-') && unicode.IsDigit(lexer.peekRune(2))) {
+				builder.WriteRune(lexer.advance())
+				if sign == '+' || sign == '-' {
+					builder.WriteRune(lexer.advance())
+				}
+				lexer.scanDigits(&builder)
+				current.kind = floatToken
+			}
+		}
+	}
+	current.text = builder.String()
+	return nil
+}
+
+
+// next scans the next token, skipping any leading whitespace.
+func (lexer *lexer) next() (token, error) {
+	for unicode.IsSpace(lexer.peekRune(0)) {
+		lexer.advance()
+	}
+	current := token{line: lexer.line, column: lexer.column}
+	if lexer.offset >= len(lexer.source) {
+		current.kind = endToken
+		return current, nil
+	}
+	r := lexer.peekRune(0)
+	switch {
+	case unicode.IsDigit(r):
+		if err := lexer.scanNumber(&current); err != nil {
+			return current, err
+		}
+	case isIdentifierStart(r):
+		builder := strings.Builder{}
+		for isIdentifierPart(lexer.peekRune(0)) {
+			builder.WriteRune(lexer.advance())
+		}
+		current.kind = identifierToken
+		current.text = builder.String()
+	case strings.ContainsRune("+-*/^!(),", r):
+		current.kind = operatorToken
+		current.text = string(lexer.advance())
+	default:
+		return current, ParseError{current.line, current.column, fmt.Sprintf("unexpected character %q", r)}
+	}
+	return current, nil
+}
+
+
+// The parser is a recursive-descent one, with the following grammar:
+//
+//   expression := term (("+" | "-") term)*
+//   term       := unary (("*" | "/") unary)*
+//   unary      := "-" unary | power
+//   power      := postfix ("^" exponent)?
+//   exponent   := "-" exponent | power
+//   postfix    := primary "!"*
+//   primary    := number | identifier | identifier "(" arguments ")" | "(" expression ")"
+//
+// Powers are right-associative and the negation has less precedence than
+// the power, so -X^2 stands for -(X^2).
+type parser struct {
+	lexer   *lexer
+	current token
+}
+
+
+func (parser *parser) advance() error {
+	if next, err := parser.lexer.next(); err != nil {
+		return err
+	} else {
+		parser.current = next
+		return nil
+	}
+}
+
+
+func (parser *parser) errorAt(at token, format string, args ...interface{}) error {
+	return ParseError{at.line, at.column, fmt.Sprintf(format, args...)}
+}
+
+
+func (parser *parser) isOperator(operator string) bool {
+	return parser.current.kind == operatorToken && parser.current.text == operator
+}
+
+
+func (parser *parser) expect(operator string) error {
+	if !parser.isOperator(operator) {
+		return parser.errorAt(parser.current, "unexpected %s, expected %q", parser.current, operator)
+	}
+	return parser.advance()
+}
+
+
+func (parser *parser) parseExpression() (Expression, error) {
+	first, err := parser.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	terms := []Expression{first}
+	for parser.isOperator("+") || parser.isOperator("-") {
+		negate := parser.isOperator("-")
+		if err := parser.advance(); err != nil {
+			return nil, err
+		}
+		term, err := parser.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		// Subtracted terms are negated, even numbers: X - 3 stands for
+		// X + Negated(3), which is the way it is represented.
+		if negate {
+			term = Negated(term)
+		}
+		terms = append(terms, term)
+	}
+	if len(terms) == 1 {
+		return first, nil
+	}
+	return Add(terms...), nil
+}
+
+
+func (parser *parser) parseTerm() (Expression, error) {
+	first, err := parser.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	factors := []Expression{first}
+	for parser.isOperator("*") || parser.isOperator("/") {
+		invert := parser.isOperator("/")
+		if err := parser.advance(); err != nil {
+			return nil, err
+		}
+		factor, err := parser.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if invert {
+			factor = Inverse(factor)
+		}
+		factors = append(factors, factor)
+	}
+	if len(factors) == 1 {
+		return first, nil
+	}
+	return Mul(factors...), nil
+}
+
+
+// negateOperand negates an operand, turning negated numbers into negative constants.
+func negateOperand(operand Expression) Expression {
+	if constant, ok := operand.(Constant); ok {
+		return Constant{ops.Neg(constant.number)}
+	}
+	return Negated(operand)
+}
+
+
+func (parser *parser) parseUnary() (Expression, error) {
+	if parser.isOperator("-") {
+		if err := parser.advance(); err != nil {
+			return nil, err
+		}
+		if operand, err := parser.parseUnary(); err != nil {
+			return nil, err
+		} else {
+			return negateOperand(operand), nil
+		}
+	}
+	return parser.parsePower()
+}
+
+
+func (parser *parser) parseExponent() (Expression, error) {
+	if parser.isOperator("-") {
+		if err := parser.advance(); err != nil {
+			return nil, err
+		}
+		if operand, err := parser.parseExponent(); err != nil {
+			return nil, err
+		} else {
+			return negateOperand(operand), nil
+		}
+	}
+	return parser.parsePower()
+}
+
+
+func (parser *parser) parsePower() (Expression, error) {
+	base, err := parser.parsePostfix()
+	if err != nil {
+		return nil, err
+	}
+	if !parser.isOperator("^") {
+		return base, nil
+	}
+	if err := parser.advance(); err != nil {
+		return nil, err
+	}
+	exponent, err := parser.parseExponent()
+	if err != nil {
+		return nil, err
+	}
+	// X^-1 is the way inverses are represented.
+	if constant, ok := exponent.(Constant); ok {
+		if value, ok := constant.number.(*big.Int); ok && value.IsInt64() && value.Int64() == -1 {
+			return Inverse(base), nil
+		}
+	}
+	return Pow(base, exponent), nil
+}
+
+
+func (parser *parser) parsePostfix() (Expression, error) {
+	operand, err := parser.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for parser.isOperator("!") {
+		if err := parser.advance(); err != nil {
+			return nil, err
+		}
+		operand = Factorial(operand)
+	}
+	return operand, nil
+}
+
+
+func parseNumber(number token) (sets.Number, bool) {
+	switch number.kind {
+	case intToken:
+		return big.NewInt(0).SetString(number.text, 10)
+	case ratToken:
+		return big.NewRat(0, 1).SetString(number.text)
+	default:
+		// Decimals get, at least, the precision of a float64 number.
+		// Longer literals get enough bits to keep all their digits.
+		digits := 0
+		for _, r := range number.text {
+			if r == 'e' || r == 'E' {
+				break
+			} else if unicode.IsDigit(r) {
+				digits++
+			}
+		}
+		precision := uint(math.Ceil(float64(digits) * math.Log2(10)))
+		if precision < 53 {
+			precision = 53
+		}
+		value, _, err := big.ParseFloat(number.text, 10, precision, big.ToNearestEven)
+		return value, err == nil
+	}
+}
+
+
+func (parser *parser) parsePrimary() (Expression, error) {
+	current := parser.current
+	switch current.kind {
+	case intToken, ratToken, floatToken:
+		number, ok := parseNumber(current)
+		if !ok {
+			return nil, parser.errorAt(current, "invalid %s", current)
+		}
+		if err := parser.advance(); err != nil {
+			return nil, err
+		}
+		return Constant{number}, nil
+	case identifierToken:
+		if err := parser.advance(); err != nil {
+			return nil, err
+		}
+		if parser.isOperator("(") {
+			return parser.parseCall(current)
+		}
+		return Var(current.text), nil
+	case operatorToken:
+		if current.text == "(" {
+			if err := parser.advance(); err != nil {
+				return nil, err
+			}
+			inner, err := parser.parseExpression()
+			if err != nil {
+				return nil, err
+			}
+			if err := parser.expect(")"); err != nil {
+				return nil, err
+			}
+			return inner, nil
+		}
+	}
+	return nil, parser.errorAt(current, "unexpected %s, expected a number, a variable, a function call or \"(\"", current)
+}
+
+
+func (parser *parser) parseCall(name token) (Expression, error) {
+	builder, ok := LookupFunction(name.text)
+	if !ok {
+		return nil, parser.errorAt(name, "unknown function %q", name.text)
+	}
+	if err := parser.expect("("); err != nil {
+		return nil, err
+	}
+	arguments := []Expression{}
+	if !parser.isOperator(")") {
+		for {
+			argument, err := parser.parseExpression()
+			if err != nil {
+				return nil, err
+			}
+			arguments = append(arguments, argument)
+			if !parser.isOperator(",") {
+				break
+			}
+			if err := parser.advance(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := parser.expect(")"); err != nil {
+		return nil, err
+	}
+	if result, err := builder(arguments...); err != nil {
+		return nil, parser.errorAt(name, "%s: %s", name.text, err)
+	} else {
+		return result, nil
+	}
+}
+
+
+// Parse builds an expression out of its infix representation. The understood
+// syntax is the same String() emits: +, -, *, /, ^, !, parentheses, function
+// calls (e.g. ln(X), log(2, X), sin(X), Round(X, 0), Frac(X), or any function
+// registered via RegisterFunction), integer (3), rational (3/4) and decimal
+// (3.75) literals, and variable names made of letters, digits and underscores.
+// Negative numbers become negative constants (-3), while subtracted ones become
+// negated constants (X - 3 is X + Negated(3)), so the representation of an
+// expression parses back to it. The only exception are negative constants added
+// after other terms: X + (-3) is also represented as X - 3, so it parses back
+// as X + Negated(3) instead.
+// Goal-seeking and integral expressions cannot be parsed, since they depend on algorithms.
+// On failure, a ParseError telling the line and column is returned.
+func Parse(source string) (Expression, error) {
+	parser := &parser{lexer: &lexer{[]rune(source), 0, 1, 1}}
+	if err := parser.advance(); err != nil {
+		return nil, err
+	}
+	expression, err := parser.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+	if parser.current.kind != endToken {
+		return nil, parser.errorAt(parser.current, "unexpected %s, expected an operator or end of input", parser.current)
+	}
+	return expression, nil
+}
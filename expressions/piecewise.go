@@ -0,0 +1,838 @@
+package expressions
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"github.com/universe-10th/calculus/errors"
+	"github.com/universe-10th/calculus/ops"
+	"github.com/universe-10th/calculus/sets"
+)
+
+
+// Comparison tells which relation a comparison expression checks.
+type Comparison int
+const (
+	LessThan Comparison = iota
+	LessThanOrEqual
+	GreaterThan
+	GreaterThanOrEqual
+	EqualTo
+	NotEqualTo
+)
+
+
+// The standard names and the LaTeX relations of the comparisons.
+var comparisonNames = []string{"lt", "le", "gt", "ge", "eq", "ne"}
+var comparisonRelations = []string{"<", `\leq`, ">", `\geq`, "=", `\neq`}
+
+
+// holds tells whether the relation holds, given the result of comparing both values.
+func (comparison Comparison) holds(cmp int) bool {
+	switch comparison {
+	case LessThan:
+		return cmp < 0
+	case LessThanOrEqual:
+		return cmp <= 0
+	case GreaterThan:
+		return cmp > 0
+	case GreaterThanOrEqual:
+		return cmp >= 0
+	case EqualTo:
+		return cmp == 0
+	default:
+		return cmp != 0
+	}
+}
+
+
+// truth converts a boolean to the value of a comparison: 1 (true) or 0 (false).
+func truth(value bool) sets.Number {
+	if value {
+		return big.NewInt(1)
+	}
+	return big.NewInt(0)
+}
+
+
+// isTrue tells whether the value of a condition stands for true: any non-zero value.
+func isTrue(value sets.Number) bool {
+	return !ops.IsZero(value)
+}
+
+
+// ComparisonExpr is a predicate comparing two expressions. It evaluates to 1
+// if the relation holds, and 0 otherwise, so comparisons can be used both as
+// piecewise conditions and as factors (e.g. Rate * (X > Threshold)). For the
+// same reason, the product of comparisons works as their conjunction.
+type ComparisonExpr struct {
+	FunctionExpr
+	comparison  Comparison
+	left, right Expression
+}
+
+
+// Curry tries currying both sides, and then attempts simplifying.
+func (comparison ComparisonExpr) Curry(args Arguments) (Expression, error) {
+	if curriedLeft, err := comparison.left.Curry(args); err != nil {
+		return nil, err
+	} else if curriedRight, err := comparison.right.Curry(args); err != nil {
+		return nil, err
+	} else {
+		return Compare(curriedLeft, comparison.comparison, curriedRight).Simplify()
+	}
+}
+
+
+// Substitute replaces the variables in both sides, and rebuilds the node.
+func (comparison ComparisonExpr) Substitute(substitutions Substitutions) (Expression, error) {
+	if substitutedLeft, err := comparison.left.Substitute(substitutions); err != nil {
+		return nil, err
+	} else if substitutedRight, err := comparison.right.Substitute(substitutions); err != nil {
+		return nil, err
+	} else {
+		return Compare(substitutedLeft, comparison.comparison, substitutedRight), nil
+	}
+}
+
+
+// Evaluate computes both sides and compares them, returning 1 or 0.
+func (comparison ComparisonExpr) Evaluate(args Arguments) (sets.Number, error) {
+	if left, err := comparison.left.Evaluate(args); err != nil {
+		return nil, err
+	} else if right, err := comparison.right.Evaluate(args); err != nil {
+		return nil, err
+	} else {
+		return truth(comparison.comparison.holds(ops.Cmp(left, right))), nil
+	}
+}
+
+
+// Derivative is 0 where both sides differ, and undefined where they are equal
+// (the comparison jumps there): it is a piecewise expression with no fallback.
+func (comparison ComparisonExpr) Derivative(wrt Variable) (Expression, error) {
+	if comparison.IsConstant(wrt) {
+		return Num(0), nil
+	}
+	return Piecewise([]Branch{{Compare(comparison.left, NotEqualTo, comparison.right), Num(0)}}, nil), nil
+}
+
+
+// CollectVariables digs into both sides.
+func (comparison ComparisonExpr) CollectVariables(variables Variables) {
+	comparison.left.CollectVariables(variables)
+	comparison.right.CollectVariables(variables)
+}
+
+
+// IsConstant tells whether both sides are constant with respect to the given variable.
+func (comparison ComparisonExpr) IsConstant(wrt Variable) bool {
+	return comparison.left.IsConstant(wrt) && comparison.right.IsConstant(wrt)
+}
+
+
+// Simplify simplifies both sides. If both become constant, they are compared.
+// Also, comparing an expression to itself yields a constant.
+func (comparison ComparisonExpr) Simplify() (Expression, error) {
+	if left, err := comparison.left.Simplify(); err != nil {
+		return nil, err
+	} else if right, err := comparison.right.Simplify(); err != nil {
+		return nil, err
+	} else {
+		leftConstant, leftOk := left.(Constant)
+		rightConstant, rightOk := right.(Constant)
+		if leftOk && rightOk {
+			return Constant{truth(comparison.comparison.holds(ops.Cmp(leftConstant.number, rightConstant.number)))}, nil
+		} else if equivalent(left, right) {
+			return Constant{truth(comparison.comparison.holds(0))}, nil
+		} else {
+			return Compare(left, comparison.comparison, right), nil
+		}
+	}
+}
+
+
+// Arguments returns both sides, left first.
+func (comparison ComparisonExpr) Arguments() []Expression {
+	return []Expression{comparison.left, comparison.right}
+}
+
+
+// String represents the comparison as a function, like lt(X, 0).
+func (comparison ComparisonExpr) String() string {
+	return FunctionDisplay(comparison)
+}
+
+
+// relation renders the comparison as a LaTeX relation, like X < 0.
+func (comparison ComparisonExpr) relation() string {
+	return fmt.Sprintf("%s %s %s", LaTeX(comparison.left), comparisonRelations[comparison.comparison], LaTeX(comparison.right))
+}
+
+
+// LaTeX renders the comparison as an Iverson bracket, like [X < 0].
+func (comparison ComparisonExpr) LaTeX() string {
+	return `\left[` + comparison.relation() + `\right]`
+}
+
+
+// Compare constructs a comparison expression: left <comparison> right.
+func Compare(left Expression, comparison Comparison, right Expression) Expression {
+	return ComparisonExpr{FunctionExpr{comparisonNames[comparison]}, comparison, left, right}
+}
+
+
+// Less constructs a left < right comparison.
+func Less(left, right Expression) Expression {
+	return Compare(left, LessThan, right)
+}
+
+
+// LessOrEqual constructs a left <= right comparison.
+func LessOrEqual(left, right Expression) Expression {
+	return Compare(left, LessThanOrEqual, right)
+}
+
+
+// Greater constructs a left > right comparison.
+func Greater(left, right Expression) Expression {
+	return Compare(left, GreaterThan, right)
+}
+
+
+// GreaterOrEqual constructs a left >= right comparison.
+func GreaterOrEqual(left, right Expression) Expression {
+	return Compare(left, GreaterThanOrEqual, right)
+}
+
+
+// Equals constructs a left == right comparison.
+func Equals(left, right Expression) Expression {
+	return Compare(left, EqualTo, right)
+}
+
+
+// NotEquals constructs a left != right comparison.
+func NotEquals(left, right Expression) Expression {
+	return Compare(left, NotEqualTo, right)
+}
+
+
+// Branch is a piece of a piecewise expression: its value applies when its
+// condition holds (i.e. evaluates to a non-zero value).
+type Branch struct {
+	Condition, Value Expression
+}
+
+
+// PiecewiseExpr is an expression taking the value of the first branch whose
+// condition holds, or a fallback value (if any) when no condition holds. Only
+// the conditions up to the matching one, and the matching value, are evaluated.
+type PiecewiseExpr struct {
+	FunctionExpr
+	branches  []Branch
+	otherwise Expression
+}
+
+
+// resolve transforms the conditions in order, pruning the branches whose
+// conditions become constant: false ones are dropped, and the first true one
+// becomes the fallback (the remaining branches are dropped). Only the values
+// of the remaining branches are transformed. If no branch remains, the fallback
+// is returned instead (or ErrNoMatchingBranch if there is no fallback).
+func (piecewise PiecewiseExpr) resolve(transform func(Expression) (Expression, error)) (Expression, error) {
+	branches := []Branch{}
+	var otherwise Expression
+	for _, branch := range piecewise.branches {
+		condition, err := transform(branch.Condition)
+		if err != nil {
+			return nil, err
+		}
+		if constant, ok := condition.(Constant); ok && !isTrue(constant.number) {
+			continue
+		}
+		value, err := transform(branch.Value)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := condition.(Constant); ok {
+			otherwise = value
+			break
+		}
+		branches = append(branches, Branch{condition, value})
+	}
+	if otherwise == nil && piecewise.otherwise != nil {
+		var err error
+		if otherwise, err = transform(piecewise.otherwise); err != nil {
+			return nil, err
+		}
+	}
+	if len(branches) == 0 {
+		if otherwise == nil {
+			return nil, errors.ErrNoMatchingBranch
+		}
+		return otherwise, nil
+	}
+	return Piecewise(branches, otherwise), nil
+}
+
+
+// Curry curries the conditions in order, pruning the branches whose conditions
+// become constant (see Simplify). Pruned values are not curried at all.
+func (piecewise PiecewiseExpr) Curry(args Arguments) (Expression, error) {
+	if curried, err := piecewise.resolve(func(expression Expression) (Expression, error) {
+		return expression.Curry(args)
+	}); err != nil {
+		return nil, err
+	} else {
+		return curried.Simplify()
+	}
+}
+
+
+// Substitute replaces the variables in all the conditions and values, and rebuilds the node.
+func (piecewise PiecewiseExpr) Substitute(substitutions Substitutions) (Expression, error) {
+	if arguments, err := substituteAll(piecewise.Arguments(), substitutions); err != nil {
+		return nil, err
+	} else {
+		return buildPiecewise(arguments...)
+	}
+}
+
+
+// Evaluate evaluates the conditions in order, and then the value of the first
+// one holding. If none holds, the fallback value is evaluated instead, or the
+// ErrNoMatchingBranch error is returned if there is no fallback.
+func (piecewise PiecewiseExpr) Evaluate(args Arguments) (sets.Number, error) {
+	for _, branch := range piecewise.branches {
+		if condition, err := branch.Condition.Evaluate(args); err != nil {
+			return nil, err
+		} else if isTrue(condition) {
+			return branch.Value.Evaluate(args)
+		}
+	}
+	if piecewise.otherwise == nil {
+		return nil, errors.ErrNoMatchingBranch
+	}
+	return piecewise.otherwise.Evaluate(args)
+}
+
+
+// Derivative is computed branch by branch: the conditions are kept, and the
+// values (and the fallback) are replaced by their derivatives. The derivative
+// at the boundaries between branches is the derivative of the matching branch,
+// even if the expression is not derivable there.
+func (piecewise PiecewiseExpr) Derivative(wrt Variable) (Expression, error) {
+	branches := make([]Branch, len(piecewise.branches))
+	for index, branch := range piecewise.branches {
+		if derivative, err := branch.Value.Derivative(wrt); err != nil {
+			return nil, err
+		} else {
+			branches[index] = Branch{branch.Condition, derivative}
+		}
+	}
+	var otherwise Expression
+	if piecewise.otherwise != nil {
+		var err error
+		if otherwise, err = piecewise.otherwise.Derivative(wrt); err != nil {
+			return nil, err
+		}
+	}
+	return Piecewise(branches, otherwise).Simplify()
+}
+
+
+// CollectVariables digs into all the conditions and values.
+func (piecewise PiecewiseExpr) CollectVariables(variables Variables) {
+	for _, argument := range piecewise.Arguments() {
+		argument.CollectVariables(variables)
+	}
+}
+
+
+// IsConstant tells whether all the conditions and values are constant with respect to the given variable.
+func (piecewise PiecewiseExpr) IsConstant(wrt Variable) bool {
+	for _, argument := range piecewise.Arguments() {
+		if !argument.IsConstant(wrt) {
+			return false
+		}
+	}
+	return true
+}
+
+
+// Simplify simplifies the conditions in order, pruning the branches whose
+// conditions become constant: false ones are dropped, and the first true one
+// becomes the fallback. Then the values of the remaining branches are simplified.
+// Trailing branches whose values are equal to the fallback are also dropped.
+func (piecewise PiecewiseExpr) Simplify() (Expression, error) {
+	simplified, err := piecewise.resolve(func(expression Expression) (Expression, error) {
+		return expression.Simplify()
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result, ok := simplified.(PiecewiseExpr); ok && result.otherwise != nil {
+		// Trailing branches having the same value of the fallback are irrelevant.
+		last := len(result.branches)
+		for last > 0 && equivalent(result.branches[last - 1].Value, result.otherwise) {
+			last--
+		}
+		if last == 0 {
+			return result.otherwise, nil
+		}
+		return Piecewise(result.branches[:last], result.otherwise), nil
+	}
+	return simplified, nil
+}
+
+
+// Arguments returns the condition and value of each branch, in order, and then
+// the fallback value, if any.
+func (piecewise PiecewiseExpr) Arguments() []Expression {
+	arguments := make([]Expression, 0, 2 * len(piecewise.branches) + 1)
+	for _, branch := range piecewise.branches {
+		arguments = append(arguments, branch.Condition, branch.Value)
+	}
+	if piecewise.otherwise != nil {
+		arguments = append(arguments, piecewise.otherwise)
+	}
+	return arguments
+}
+
+
+// String represents the piecewise expression as a function, like piecewise(lt(X, 0), -X, X).
+func (piecewise PiecewiseExpr) String() string {
+	return FunctionDisplay(piecewise)
+}
+
+
+// LaTeX renders the piecewise expression as cases.
+func (piecewise PiecewiseExpr) LaTeX() string {
+	lines := []string{}
+	for _, branch := range piecewise.branches {
+		condition := LaTeX(branch.Condition)
+		if comparison, ok := branch.Condition.(ComparisonExpr); ok {
+			condition = comparison.relation()
+		}
+		lines = append(lines, fmt.Sprintf(`%s & \text{if } %s`, LaTeX(branch.Value), condition))
+	}
+	if piecewise.otherwise != nil {
+		lines = append(lines, fmt.Sprintf(`%s & \text{otherwise}`, LaTeX(piecewise.otherwise)))
+	}
+	return `\begin{cases} ` + strings.Join(lines, ` \\ `) + ` \end{cases}`
+}
+
+
+// Piecewise constructs a piecewise expression out of its branches (in order)
+// and its fallback value (nil if there is none). With no branches, the fallback
+// is returned as is (and, if there is no fallback either, the expression fails
+// to evaluate with ErrNoMatchingBranch).
+func Piecewise(branches []Branch, otherwise Expression) Expression {
+	if len(branches) == 0 && otherwise != nil {
+		return otherwise
+	}
+	return PiecewiseExpr{FunctionExpr{"piecewise"}, branches, otherwise}
+}
+
+
+// buildPiecewise constructs a piecewise expression out of its arguments, as
+// returned by Arguments: pairs of condition and value, and maybe a fallback.
+func buildPiecewise(arguments ...Expression) (Expression, error) {
+	branches := make([]Branch, len(arguments) / 2)
+	for index := range branches {
+		branches[index] = Branch{arguments[2 * index], arguments[2 * index + 1]}
+	}
+	var otherwise Expression
+	if len(arguments) % 2 == 1 {
+		otherwise = arguments[len(arguments) - 1]
+	}
+	return Piecewise(branches, otherwise), nil
+}
+
+
+// substituteAll replaces the variables in each expression.
+func substituteAll(expressions []Expression, substitutions Substitutions) ([]Expression, error) {
+	substituted := make([]Expression, len(expressions))
+	for index, expression := range expressions {
+		if result, err := expression.Substitute(substitutions); err != nil {
+			return nil, err
+		} else {
+			substituted[index] = result
+		}
+	}
+	return substituted, nil
+}
+
+
+// ExtremumExpr is the minimum (or maximum) of its arguments.
+type ExtremumExpr struct {
+	FunctionExpr
+	maximum   bool
+	arguments []Expression
+}
+
+
+// rebuild constructs an extremum of the same kind, with other arguments.
+func (extremum ExtremumExpr) rebuild(arguments []Expression) Expression {
+	if extremum.maximum {
+		return Max(arguments...)
+	}
+	return Min(arguments...)
+}
+
+
+// wins tells whether a value replaces the current extremum value.
+func (extremum ExtremumExpr) wins(value, current sets.Number) bool {
+	if extremum.maximum {
+		return ops.Cmp(value, current) > 0
+	}
+	return ops.Cmp(value, current) < 0
+}
+
+
+// Curry tries currying each argument, and then attempts simplifying.
+func (extremum ExtremumExpr) Curry(args Arguments) (Expression, error) {
+	curried := make([]Expression, len(extremum.arguments))
+	for index, argument := range extremum.arguments {
+		if result, err := argument.Curry(args); err != nil {
+			return nil, err
+		} else {
+			curried[index] = result
+		}
+	}
+	return extremum.rebuild(curried).Simplify()
+}
+
+
+// Substitute replaces the variables in each argument, and rebuilds the node.
+func (extremum ExtremumExpr) Substitute(substitutions Substitutions) (Expression, error) {
+	if substituted, err := substituteAll(extremum.arguments, substitutions); err != nil {
+		return nil, err
+	} else {
+		return extremum.rebuild(substituted), nil
+	}
+}
+
+
+// Evaluate evaluates all the arguments and picks the minimum (maximum) value.
+// It is an error if there are no arguments.
+func (extremum ExtremumExpr) Evaluate(args Arguments) (sets.Number, error) {
+	if len(extremum.arguments) == 0 {
+		return nil, errors.ErrWrongArgumentsCount
+	}
+	var result sets.Number
+	for _, argument := range extremum.arguments {
+		if value, err := argument.Evaluate(args); err != nil {
+			return nil, err
+		} else if result == nil || extremum.wins(value, result) {
+			result = value
+		}
+	}
+	return result, nil
+}
+
+
+// Derivative is a piecewise expression having the derivative of each argument,
+// under the condition of that argument being the extremum (the first one, on
+// ties). The last argument's derivative is the fallback. It is an error if
+// there are no arguments.
+func (extremum ExtremumExpr) Derivative(wrt Variable) (Expression, error) {
+	if len(extremum.arguments) == 0 {
+		return nil, errors.ErrWrongArgumentsCount
+	} else if extremum.IsConstant(wrt) {
+		return Num(0), nil
+	}
+	comparison := LessThanOrEqual
+	if extremum.maximum {
+		comparison = GreaterThanOrEqual
+	}
+	last := len(extremum.arguments) - 1
+	branches := make([]Branch, last)
+	for index, argument := range extremum.arguments[:last] {
+		derivative, err := argument.Derivative(wrt)
+		if err != nil {
+			return nil, err
+		}
+		comparisons := []Expression{}
+		for otherIndex, other := range extremum.arguments {
+			if otherIndex > index {
+				comparisons = append(comparisons, Compare(argument, comparison, other))
+			}
+		}
+		if len(comparisons) == 1 {
+			branches[index] = Branch{comparisons[0], derivative}
+		} else {
+			branches[index] = Branch{Mul(comparisons...), derivative}
+		}
+	}
+	if otherwise, err := extremum.arguments[last].Derivative(wrt); err != nil {
+		return nil, err
+	} else {
+		return Piecewise(branches, otherwise).Simplify()
+	}
+}
+
+
+// CollectVariables digs into all the arguments.
+func (extremum ExtremumExpr) CollectVariables(variables Variables) {
+	for _, argument := range extremum.arguments {
+		argument.CollectVariables(variables)
+	}
+}
+
+
+// IsConstant tells whether all the arguments are constant with respect to the given variable.
+func (extremum ExtremumExpr) IsConstant(wrt Variable) bool {
+	for _, argument := range extremum.arguments {
+		if !argument.IsConstant(wrt) {
+			return false
+		}
+	}
+	return true
+}
+
+
+// Simplify simplifies the arguments, flattening nested extrema of the same kind,
+// dropping repeated arguments and replacing the constant ones by their extremum
+// (placed last). A single remaining argument is returned as is. It is an error
+// if there are no arguments.
+func (extremum ExtremumExpr) Simplify() (Expression, error) {
+	if len(extremum.arguments) == 0 {
+		return nil, errors.ErrWrongArgumentsCount
+	}
+	arguments := []Expression{}
+	var constant sets.Number
+	pending := append([]Expression{}, extremum.arguments...)
+	for len(pending) > 0 {
+		simplified, err := pending[0].Simplify()
+		if err != nil {
+			return nil, err
+		}
+		pending = pending[1:]
+		if nested, ok := simplified.(ExtremumExpr); ok && nested.maximum == extremum.maximum {
+			pending = append(append([]Expression{}, nested.arguments...), pending...)
+			continue
+		}
+		if number, ok := simplified.(Constant); ok {
+			if constant == nil || extremum.wins(number.number, constant) {
+				constant = number.number
+			}
+			continue
+		}
+		repeated := false
+		for _, argument := range arguments {
+			if equivalent(argument, simplified) {
+				repeated = true
+				break
+			}
+		}
+		if !repeated {
+			arguments = append(arguments, simplified)
+		}
+	}
+	if constant != nil {
+		arguments = append(arguments, Constant{constant})
+	}
+	if len(arguments) == 1 {
+		return arguments[0], nil
+	}
+	return extremum.rebuild(arguments), nil
+}
+
+
+// Arguments returns the arguments, in order.
+func (extremum ExtremumExpr) Arguments() []Expression {
+	return extremum.arguments
+}
+
+
+// String represents the extremum as a function, like min(X, Y).
+func (extremum ExtremumExpr) String() string {
+	return FunctionDisplay(extremum)
+}
+
+
+// Min constructs the minimum of the given arguments (at least one: otherwise,
+// evaluating, deriving or simplifying it fails with ErrWrongArgumentsCount).
+func Min(arguments ...Expression) Expression {
+	return ExtremumExpr{FunctionExpr{"min"}, false, arguments}
+}
+
+
+// Max constructs the maximum of the given arguments (at least one: otherwise,
+// evaluating, deriving or simplifying it fails with ErrWrongArgumentsCount).
+func Max(arguments ...Expression) Expression {
+	return ExtremumExpr{FunctionExpr{"max"}, true, arguments}
+}
+
+
+// variadic wraps a constructor taking one or more arguments into a function builder.
+func variadic(constructor func(...Expression) Expression) FunctionBuilder {
+	return func(arguments ...Expression) (Expression, error) {
+		if len(arguments) == 0 {
+			return nil, errors.ErrWrongArgumentsCount
+		}
+		return constructor(arguments...), nil
+	}
+}
+
+
+// ClampExpr limits the value of its argument to the [lower, upper] range.
+type ClampExpr struct {
+	FunctionExpr
+	arg, lower, upper Expression
+}
+
+
+// Curry tries currying the argument and bounds, and then attempts simplifying.
+func (clamp ClampExpr) Curry(args Arguments) (Expression, error) {
+	if arg, err := clamp.arg.Curry(args); err != nil {
+		return nil, err
+	} else if lower, err := clamp.lower.Curry(args); err != nil {
+		return nil, err
+	} else if upper, err := clamp.upper.Curry(args); err != nil {
+		return nil, err
+	} else {
+		return Clamp(arg, lower, upper).Simplify()
+	}
+}
+
+
+// Substitute replaces the variables in the argument and bounds, and rebuilds the node.
+func (clamp ClampExpr) Substitute(substitutions Substitutions) (Expression, error) {
+	if substituted, err := substituteAll(clamp.Arguments(), substitutions); err != nil {
+		return nil, err
+	} else {
+		return Clamp(substituted[0], substituted[1], substituted[2]), nil
+	}
+}
+
+
+// clamped limits a value to the bounds. It is an error if lower > upper.
+func clamped(value, lower, upper sets.Number) (sets.Number, error) {
+	if ops.Cmp(lower, upper) > 0 {
+		return nil, errors.ErrInvalidArgument
+	} else if ops.Cmp(value, lower) < 0 {
+		return lower, nil
+	} else if ops.Cmp(value, upper) > 0 {
+		return upper, nil
+	} else {
+		return value, nil
+	}
+}
+
+
+// Evaluate evaluates the argument and bounds, and limits the value to the bounds.
+// It is an error if the lower bound is greater than the upper bound.
+func (clamp ClampExpr) Evaluate(args Arguments) (sets.Number, error) {
+	if value, err := clamp.arg.Evaluate(args); err != nil {
+		return nil, err
+	} else if lower, err := clamp.lower.Evaluate(args); err != nil {
+		return nil, err
+	} else if upper, err := clamp.upper.Evaluate(args); err != nil {
+		return nil, err
+	} else {
+		return clamped(value, lower, upper)
+	}
+}
+
+
+// Derivative is a piecewise expression: the derivative of the lower (upper)
+// bound when the argument is below (above) it, and the argument's derivative
+// otherwise.
+func (clamp ClampExpr) Derivative(wrt Variable) (Expression, error) {
+	if clamp.IsConstant(wrt) {
+		return Num(0), nil
+	} else if arg, err := clamp.arg.Derivative(wrt); err != nil {
+		return nil, err
+	} else if lower, err := clamp.lower.Derivative(wrt); err != nil {
+		return nil, err
+	} else if upper, err := clamp.upper.Derivative(wrt); err != nil {
+		return nil, err
+	} else {
+		return Piecewise([]Branch{
+			{Less(clamp.arg, clamp.lower), lower},
+			{Greater(clamp.arg, clamp.upper), upper},
+		}, arg).Simplify()
+	}
+}
+
+
+// CollectVariables digs into the argument and bounds.
+func (clamp ClampExpr) CollectVariables(variables Variables) {
+	clamp.arg.CollectVariables(variables)
+	clamp.lower.CollectVariables(variables)
+	clamp.upper.CollectVariables(variables)
+}
+
+
+// IsConstant tells whether the argument and bounds are constant with respect to the given variable.
+func (clamp ClampExpr) IsConstant(wrt Variable) bool {
+	return clamp.arg.IsConstant(wrt) && clamp.lower.IsConstant(wrt) && clamp.upper.IsConstant(wrt)
+}
+
+
+// Simplify simplifies the argument and bounds and, if all of them are constant,
+// returns the clamped constant.
+func (clamp ClampExpr) Simplify() (Expression, error) {
+	if arg, err := clamp.arg.Simplify(); err != nil {
+		return nil, err
+	} else if lower, err := clamp.lower.Simplify(); err != nil {
+		return nil, err
+	} else if upper, err := clamp.upper.Simplify(); err != nil {
+		return nil, err
+	} else {
+		argConstant, argOk := arg.(Constant)
+		lowerConstant, lowerOk := lower.(Constant)
+		upperConstant, upperOk := upper.(Constant)
+		if argOk && lowerOk && upperOk {
+			if result, err := clamped(argConstant.number, lowerConstant.number, upperConstant.number); err != nil {
+				return nil, err
+			} else {
+				return Constant{result}, nil
+			}
+		}
+		return Clamp(arg, lower, upper), nil
+	}
+}
+
+
+// Arguments returns the argument and then the lower and upper bounds.
+func (clamp ClampExpr) Arguments() []Expression {
+	return []Expression{clamp.arg, clamp.lower, clamp.upper}
+}
+
+
+// String represents the clamp as a function, like clamp(X, 0, 1).
+func (clamp ClampExpr) String() string {
+	return FunctionDisplay(clamp)
+}
+
+
+// Clamp constructs an expression limiting the argument to the [lower, upper] range.
+func Clamp(arg, lower, upper Expression) Expression {
+	return ClampExpr{FunctionExpr{"clamp"}, arg, lower, upper}
+}
+
+
+func buildClamp(arguments ...Expression) (Expression, error) {
+	if len(arguments) != 3 {
+		return nil, errors.ErrWrongArgumentsCount
+	}
+	return Clamp(arguments[0], arguments[1], arguments[2]), nil
+}
+
+
+func init() {
+	for comparison, name := range comparisonNames {
+		comparison := Comparison(comparison)
+		RegisterFunction(name, binary(func(left, right Expression) Expression {
+			return Compare(left, comparison, right)
+		}))
+	}
+	RegisterFunction("piecewise", buildPiecewise)
+	RegisterFunction("min", variadic(Min))
+	RegisterFunction("max", variadic(Max))
+	RegisterFunction("clamp", buildClamp)
+}
@@ -0,0 +1,98 @@
+package expressions
+
+import (
+	"math/big"
+	"github.com/universe-10th/calculus/errors"
+	"github.com/universe-10th/calculus/ops"
+	"github.com/universe-10th/calculus/sets"
+)
+
+
+// monomial tells the coefficient and degree of a term, if it is a monomial on
+// the given variable. Parts not depending on the variable must be evaluable.
+func monomial(term Expression, wrt Variable) (sets.Number, int, error) {
+	if term.IsConstant(wrt) {
+		if value, err := term.Evaluate(Arguments{}); err != nil {
+			return nil, 0, errors.ErrNotPolynomial
+		} else {
+			return value, 0, nil
+		}
+	}
+	switch node := term.(type) {
+	case Variable:
+		return big.NewInt(1), 1, nil
+	case NegatedExpr:
+		if coefficient, degree, err := monomial(node.arg, wrt); err != nil {
+			return nil, 0, err
+		} else {
+			return ops.Neg(coefficient), degree, nil
+		}
+	case PowExpr:
+		if base, ok := node.base.(Variable); ok && base == wrt {
+			if degree, ok := expandableExponent(node.exponent); ok {
+				return big.NewInt(1), degree, nil
+			}
+		}
+	case MulExpr:
+		coefficients := []sets.Number{}
+		total := 0
+		for _, factor := range node.factors {
+			if coefficient, degree, err := monomial(factor, wrt); err != nil {
+				return nil, 0, err
+			} else {
+				coefficients = append(coefficients, coefficient)
+				total += degree
+			}
+		}
+		return ops.Mul(coefficients...), total, nil
+	}
+	return nil, 0, errors.ErrNotPolynomial
+}
+
+
+// PolynomialCoefficients recognizes a polynomial on the given variable, once
+// expanded, and returns its coefficients from the main one to the constant one
+// (the same order support.PolynomialExpression takes them). The coefficients
+// must be numeric: other variables, if any, must be curried beforehand. The main
+// coefficient is never zero, so the zero polynomial has no coefficients at all.
+func PolynomialCoefficients(expression Expression, wrt Variable) ([]sets.Number, error) {
+	expanded, err := Expand(expression)
+	if err != nil {
+		return nil, err
+	}
+	terms := []Expression{expanded}
+	if addition, ok := expanded.(AddExpr); ok {
+		terms = addition.terms
+	}
+	byDegree := map[int]sets.Number{}
+	maxDegree := -1
+	for _, term := range terms {
+		coefficient, degree, err := monomial(term, wrt)
+		if err != nil {
+			return nil, err
+		}
+		if current, ok := byDegree[degree]; ok {
+			byDegree[degree] = ops.Add(current, coefficient)
+		} else {
+			byDegree[degree] = coefficient
+		}
+		if degree > maxDegree {
+			maxDegree = degree
+		}
+	}
+	for maxDegree >= 0 {
+		if coefficient, ok := byDegree[maxDegree]; ok && !ops.IsZero(coefficient) {
+			break
+		}
+		maxDegree--
+	}
+	coefficients := make([]sets.Number, maxDegree + 1)
+	for degree := 0; degree <= maxDegree; degree++ {
+		if coefficient, ok := byDegree[degree]; ok {
+			coefficients[maxDegree - degree] = normalizedNumber(coefficient)
+		} else {
+			coefficients[maxDegree - degree] = big.NewInt(0)
+		}
+	}
+	return coefficients, nil
+}
@@ -42,6 +42,18 @@ func (pow PowExpr) Curry(args Arguments) (Expression, error) {
 }
 
 
+// Substitute replaces the variables in the underlying base and exponent expressions, and rebuilds the node.
+func (pow PowExpr) Substitute(substitutions Substitutions) (Expression, error) {
+	if substitutedBase, err := pow.base.Substitute(substitutions); err != nil {
+		return nil, err
+	} else if substitutedExponent, err := pow.exponent.Substitute(substitutions); err != nil {
+		return nil, err
+	} else {
+		return Pow(substitutedBase, substitutedExponent), nil
+	}
+}
+
+
 // Evaluate computes the power of the expression considering base and exponent.
 // Base expression and exponent expression are first evaluated.
 func (pow PowExpr) Evaluate(args Arguments) (sets.Number, error) {
@@ -74,7 +86,7 @@ func (pow PowExpr) derivativeBySpecialCases(
 				return nil, err
 			}
 			baseFactor, _ := Ln(simplifiedBase).Simplify()
-			return Mul(Pow(simplifiedBase, simplifiedExponent), baseFactor, exponentDerivative), nil
+			return Mul(Pow(simplifiedBase, simplifiedExponent), baseFactor, exponentDerivative).Simplify()
 			// a^[f(x)]*ln(a)*[df(x)/dx]
 		}
 	} else {
@@ -87,7 +99,7 @@ func (pow PowExpr) derivativeBySpecialCases(
 				simplifiedExponent,
 				Pow(simplifiedBase, newExponent),
 				baseDerivative,
-			), nil
+			).Simplify()
 		} else {
 			if baseDerivative, err = pow.base.Derivative(wrt); err != nil {
 				return nil, err
@@ -136,25 +148,47 @@ func (pow PowExpr) IsConstant(wrt Variable) bool {
 
 // Simplify attempts a constant simplification over both base and exponent.
 // If both operands are constant, then it calculates the power into a new constant.
+// Otherwise, identities are dropped: X^1 becomes X, while X^0 and 1^X become 1.
+// Also, powers of powers are merged when the outer exponent is integer.
 func (pow PowExpr) Simplify() (Expression, error) {
 	if simplifiedBase, err := pow.base.Simplify(); err != nil {
 		return nil, err
 	} else if simplifiedExponent, err := pow.exponent.Simplify(); err != nil {
 		return nil, err
 	} else {
-		// if both are constants, calculate.
-		// otherwise, make new expression.
-		simplifiedBaseNum, okBase := simplifiedBase.(Constant)
-		simplifiedExponentNum, okPower := simplifiedExponent.(Constant)
-		if okBase && okPower {
-			if result, err := pow.wrappedPow(simplifiedBaseNum.number, simplifiedExponentNum.number); err != nil {
-				return nil, err
-			} else {
-				return Constant{result}, nil
-			}
+		return simplifiedPower(simplifiedBase, simplifiedExponent)
+	}
+}
+
+
+// simplifiedPower does the actual simplification of the power, assuming
+// both the given base and exponent are already simplified.
+func simplifiedPower(simplifiedBase, simplifiedExponent Expression) (Expression, error) {
+	// if both are constants, calculate.
+	// otherwise, make new expression.
+	simplifiedBaseNum, okBase := simplifiedBase.(Constant)
+	simplifiedExponentNum, okPower := simplifiedExponent.(Constant)
+	if okBase && okPower {
+		if result, err := (PowExpr{}).wrappedPow(simplifiedBaseNum.number, simplifiedExponentNum.number); err != nil {
+			return nil, err
+		} else {
+			return Constant{result}, nil
+		}
+	} else if okPower && ops.IsZero(simplifiedExponentNum.number) {
+		return Num(1), nil
+	} else if okPower && ops.IsOne(simplifiedExponentNum.number) {
+		return simplifiedBase, nil
+	} else if okBase && ops.IsOne(simplifiedBaseNum.number) {
+		return Num(1), nil
+	} else if inner, ok := simplifiedBase.(PowExpr); ok && okPower && sets.BelongsTo(simplifiedExponentNum.number, sets.Z) {
+		// (X^a)^n = X^(a*n) only holds in general when n is integer.
+		if exponent, err := simplifiedProduct([]Expression{inner.exponent, simplifiedExponent}); err != nil {
+			return nil, err
 		} else {
-			return Pow(simplifiedBase, simplifiedExponent), nil
+			return simplifiedPower(inner.base, exponent)
 		}
+	} else {
+		return Pow(simplifiedBase, simplifiedExponent), nil
 	}
 }
 
@@ -162,11 +196,13 @@ func (pow PowExpr) Simplify() (Expression, error) {
 // String represents the power appropriately: (X)^(Y), perhaps removing parentheses appropriately.
 func (pow PowExpr) String() string {
 	baseStr := pow.base.String()
-	if _, ok := pow.base.(SelfContained); !ok {
+	if !isSelfContained(pow.base) {
 		baseStr = "(" + baseStr + ")"
 	}
 	exponentStr := pow.exponent.String()
 	if _, ok := pow.exponent.(SelfContained); !ok {
+		// Negations are kept unwrapped: they already wrap the
+		// products they negate (X^-Y * Z would be read wrongly).
 		if _, ok := pow.exponent.(NegatedExpr); !ok {
 			exponentStr = "(" + exponentStr + ")"
 		}
@@ -204,6 +240,16 @@ func (ln LnExpr) Curry(args Arguments) (Expression, error) {
 }
 
 
+// Substitute replaces the variables in the underlying expression, and rebuilds the node.
+func (ln LnExpr) Substitute(substitutions Substitutions) (Expression, error) {
+	if substituted, err := ln.arg.Substitute(substitutions); err != nil {
+		return nil, err
+	} else {
+		return Ln(substituted), nil
+	}
+}
+
+
 // Evaluate returns the natural logarithm of the evaluated inner expression's value.
 // It returns an error if the inner value is negative.
 func (ln LnExpr) Evaluate(args Arguments) (sets.Number, error) {
@@ -302,6 +348,18 @@ func (log LogExpr) Curry(args Arguments) (Expression, error) {
 }
 
 
+// Substitute replaces the variables in the underlying base and power expressions, and rebuilds the node.
+func (log LogExpr) Substitute(substitutions Substitutions) (Expression, error) {
+	if substitutedBase, err := log.base.Substitute(substitutions); err != nil {
+		return nil, err
+	} else if substitutedPower, err := log.power.Substitute(substitutions); err != nil {
+		return nil, err
+	} else {
+		return Log(substitutedBase, substitutedPower), nil
+	}
+}
+
+
 // Evaluate computes the logarithm after evaluating both the base and the power expressions.
 // It is an error if the power (or the base) evaluates a negative value.
 func (log LogExpr) Evaluate(args Arguments) (sets.Number, error) {
@@ -400,6 +458,16 @@ func (exp ExpExpr) Curry(args Arguments) (Expression, error) {
 }
 
 
+// Substitute replaces the variables in the underlying expression, and rebuilds the node.
+func (exp ExpExpr) Substitute(substitutions Substitutions) (Expression, error) {
+	if substituted, err := exp.exponent.Substitute(substitutions); err != nil {
+		return nil, err
+	} else {
+		return Exp(substituted), nil
+	}
+}
+
+
 // Evaluate computes the value of the inner expression (the exponent) and then computes e^(that value).
 func (exp ExpExpr) Evaluate(args Arguments) (sets.Number, error) {
 	if result, err := exp.exponent.Evaluate(args); err != nil {
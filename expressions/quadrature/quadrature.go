@@ -0,0 +1,83 @@
+package quadrature
+
+import (
+	"errors"
+	"math/big"
+	"github.com/universe-10th/calculus/expressions"
+	"github.com/universe-10th/calculus/sets"
+	"github.com/universe-10th/calculus/core/integration"
+)
+
+
+// Method tells which quadrature will estimate the integrals.
+type Method int
+
+
+const (
+	// AdaptiveSimpson splits the interval where the estimated error is high.
+	AdaptiveSimpson Method = iota
+	// Romberg extrapolates the trapezoidal rule with 1, 2, 4, ... intervals.
+	Romberg
+	// GaussLegendre uses Gauss-Legendre rules of 2, 4, 8, ... nodes.
+	GaussLegendre
+)
+
+
+var ErrQuadratureBadParams = errors.New("epsilon and max evaluations must be positive, and the method must be a valid one")
+
+
+// Quadrature algorithms take a notion of tolerance (the maximum
+// estimated error), and how many times can the integrand be evaluated.
+type QuadratureAlgorithm struct {
+	method         Method
+	epsilon        *big.Float
+	maxEvaluations uint32
+	wrt            expressions.Variable
+}
+
+
+// Integrate estimates the integral using the chosen method.
+func (quadratureAlgorithm QuadratureAlgorithm) Integrate(integrand expressions.Expression, lower, upper sets.Number) (sets.Number, error) {
+	wrt := quadratureAlgorithm.wrt
+	function := func(current *big.Float) (sets.Number, error) {
+		return integrand.Evaluate(expressions.Arguments{wrt: current})
+	}
+	lowerBound := sets.UpCastOneTo(lower, sets.R).(*big.Float)
+	upperBound := sets.UpCastOneTo(upper, sets.R).(*big.Float)
+	switch quadratureAlgorithm.method {
+	case Romberg:
+		return integration.Romberg(function, lowerBound, upperBound, quadratureAlgorithm.epsilon, quadratureAlgorithm.maxEvaluations)
+	case GaussLegendre:
+		return integration.GaussLegendre(function, lowerBound, upperBound, quadratureAlgorithm.epsilon, quadratureAlgorithm.maxEvaluations)
+	default:
+		return integration.AdaptiveSimpson(function, lowerBound, upperBound, quadratureAlgorithm.epsilon, quadratureAlgorithm.maxEvaluations)
+	}
+}
+
+
+// Function type that provides the relevant arguments for the
+// instantiation of QuadratureAlgorithms.
+type QuadratureAlgorithmArgsProvider func(arguments expressions.Arguments) (epsilon *big.Float, maxEvaluations uint32)
+
+
+// Builds a factory of quadrature algorithms, using the given method. Such
+// factory can be registered (under any name) to build named integrals.
+func QuadratureFactory(method Method, argsProvider QuadratureAlgorithmArgsProvider) expressions.IntegrationAlgorithmFactory {
+	return func(arguments expressions.Arguments, wrt expressions.Variable) (expressions.IntegrationAlgorithm, error) {
+		epsilon, maxEvaluations := argsProvider(arguments)
+		if epsilon == nil || epsilon.Sign() < 1 || maxEvaluations == 0 || method < AdaptiveSimpson || method > GaussLegendre {
+			return nil, ErrQuadratureBadParams
+		} else {
+			return QuadratureAlgorithm{method, epsilon, maxEvaluations, wrt}, nil
+		}
+	}
+}
+
+
+// Builds a new definite integral expression, estimated by the given method.
+func Quadrature(
+	method Method, integrand expressions.Expression, wrt expressions.Variable, lower, upper expressions.Expression,
+	argsProvider QuadratureAlgorithmArgsProvider,
+) expressions.Expression {
+	return expressions.Integral(integrand, wrt, lower, upper, QuadratureFactory(method, argsProvider))
+}
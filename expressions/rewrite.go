@@ -0,0 +1,274 @@
+package expressions
+
+import (
+	"reflect"
+	"github.com/universe-10th/calculus/errors"
+	"github.com/universe-10th/calculus/sets"
+)
+
+
+// RewriteGuard tells whether a rule may be applied, given the expressions
+// its wildcards were bound to when matching the pattern.
+type RewriteGuard func(bindings Substitutions) bool
+
+
+// ConstantGuard requires the expression bound to the wildcard to be constant
+// with respect to the given variable.
+func ConstantGuard(wildcard, wrt Variable) RewriteGuard {
+	return func(bindings Substitutions) bool {
+		bound, ok := bindings[wildcard]
+		return ok && bound.IsConstant(wrt)
+	}
+}
+
+
+// NumericGuard requires the expression bound to the wildcard to be a number
+// belonging to the given set (e.g. sets.Z for integer exponents).
+func NumericGuard(wildcard Variable, set sets.Set) RewriteGuard {
+	return func(bindings Substitutions) bool {
+		constant, ok := bindings[wildcard].(Constant)
+		return ok && sets.BelongsTo(constant.number, set)
+	}
+}
+
+
+// TypeGuard requires the expression bound to the wildcard to be of the same
+// node type of the given sample (e.g. TypeGuard(A, X) requires a variable).
+func TypeGuard(wildcard Variable, sample Expression) RewriteGuard {
+	return func(bindings Substitutions) bool {
+		return reflect.TypeOf(bindings[wildcard]) == reflect.TypeOf(sample)
+	}
+}
+
+
+// RewriteRule replaces the expressions matching a pattern with a template.
+// Wildcards are variables in the pattern that match any expression (the same
+// wildcard must match equal expressions everywhere it appears), while other
+// variables only match themselves. The template is instantiated by replacing
+// the wildcards with the expressions they were bound to. Commutative nodes
+// (additions and multiplications) match in any order and, at the root of the
+// pattern, they may match only some of the terms (factors): the remaining
+// ones are kept alongside the instantiated template.
+type RewriteRule struct {
+	name      string
+	pattern   Expression
+	template  Expression
+	wildcards Variables
+	guards    []RewriteGuard
+}
+
+
+// NewRewriteRule creates a rule, checking that all the wildcards used in the
+// template are present in the pattern.
+func NewRewriteRule(
+	name string, pattern, template Expression, wildcards []Variable, guards ...RewriteGuard,
+) (RewriteRule, error) {
+	wildcardSet := Variables{}
+	for _, wildcard := range wildcards {
+		wildcardSet[wildcard] = true
+	}
+	patternVariables := Variables{}
+	pattern.CollectVariables(patternVariables)
+	templateVariables := Variables{}
+	template.CollectVariables(templateVariables)
+	for variable := range templateVariables {
+		if wildcardSet[variable] && !patternVariables[variable] {
+			return RewriteRule{}, errors.ErrUnboundWildcard
+		}
+	}
+	return RewriteRule{name, pattern, template, wildcardSet, guards}, nil
+}
+
+
+// Name returns the name of the rule, for display purposes.
+func (rule RewriteRule) Name() string {
+	return rule.name
+}
+
+
+// bind tells whether the expression matches the pattern, and returns the new
+// bindings if so. The given bindings are never modified.
+func (rule RewriteRule) bind(pattern, expression Expression, bindings Substitutions) (Substitutions, bool) {
+	if variable, ok := pattern.(Variable); ok && rule.wildcards[variable] {
+		if bound, ok := bindings[variable]; ok {
+			return bindings, Equal(bound, expression)
+		}
+		extended := Substitutions{variable: expression}
+		for key, value := range bindings {
+			extended[key] = value
+		}
+		return extended, true
+	}
+	if nodeLabel(pattern) != nodeLabel(expression) {
+		return nil, false
+	}
+	patternArguments := nodeArguments(pattern)
+	arguments := nodeArguments(expression)
+	if len(patternArguments) != len(arguments) {
+		return nil, false
+	}
+	if isCommutative(pattern) {
+		result, _, ok := rule.bindUnordered(patternArguments, arguments, make([]bool, len(arguments)), bindings)
+		return result, ok
+	}
+	for index, patternArgument := range patternArguments {
+		var ok bool
+		if bindings, ok = rule.bind(patternArgument, arguments[index], bindings); !ok {
+			return nil, false
+		}
+	}
+	return bindings, true
+}
+
+
+// bindUnordered matches each of the pattern arguments with a different, not yet
+// used, argument (in any order), backtracking when needed. It tells which of
+// the arguments were used.
+func (rule RewriteRule) bindUnordered(
+	patternArguments, arguments []Expression, used []bool, bindings Substitutions,
+) (Substitutions, []bool, bool) {
+	if len(patternArguments) == 0 {
+		return bindings, used, true
+	}
+	for index, argument := range arguments {
+		if used[index] {
+			continue
+		}
+		if extended, ok := rule.bind(patternArguments[0], argument, bindings); ok {
+			used[index] = true
+			if result, resultUsed, ok := rule.bindUnordered(patternArguments[1:], arguments, used, extended); ok {
+				return result, resultUsed, true
+			}
+			used[index] = false
+		}
+	}
+	return nil, nil, false
+}
+
+
+// accepts tells whether all the guards accept the bindings.
+func (rule RewriteRule) accepts(bindings Substitutions) bool {
+	for _, guard := range rule.guards {
+		if !guard(bindings) {
+			return false
+		}
+	}
+	return true
+}
+
+
+// Apply tries the rule at the root of the expression (not in its inner
+// expressions), telling whether it matched.
+func (rule RewriteRule) Apply(expression Expression) (Expression, bool, error) {
+	patternArguments := nodeArguments(rule.pattern)
+	arguments := nodeArguments(expression)
+	if isCommutative(rule.pattern) && nodeLabel(rule.pattern) == nodeLabel(expression) &&
+	   len(patternArguments) < len(arguments) {
+		// Match only some of the terms (factors), and keep the remaining ones.
+		bindings, used, ok := rule.bindUnordered(patternArguments, arguments, make([]bool, len(arguments)), Substitutions{})
+		if !ok || !rule.accepts(bindings) {
+			return nil, false, nil
+		}
+		instantiated, err := rule.template.Substitute(bindings)
+		if err != nil {
+			return nil, false, err
+		}
+		remaining := []Expression{instantiated}
+		for index, argument := range arguments {
+			if !used[index] {
+				remaining = append(remaining, argument)
+			}
+		}
+		result, err := withArguments(expression, remaining)
+		return result, err == nil, err
+	}
+	if bindings, ok := rule.bind(rule.pattern, expression, Substitutions{}); !ok || !rule.accepts(bindings) {
+		return nil, false, nil
+	} else if instantiated, err := rule.template.Substitute(bindings); err != nil {
+		return nil, false, err
+	} else {
+		return instantiated, true, nil
+	}
+}
+
+
+// A rewriter keeps the rules, and how many steps are left.
+type rewriter struct {
+	rules     []RewriteRule
+	remaining int
+}
+
+
+// rewrite brings the inner expressions to their fixed point first, and then
+// applies the rules on the node until none matches. Each time a rule matches,
+// the new node is rewritten again, since new matches may appear in it.
+func (rewriter *rewriter) rewrite(expression Expression) (Expression, error) {
+	current, err := mapArguments(expression, rewriter.rewrite)
+	if err != nil {
+		return nil, err
+	}
+	for _, rule := range rewriter.rules {
+		if rewritten, ok, err := rule.Apply(current); err != nil {
+			return nil, err
+		} else if ok {
+			if rewriter.remaining == 0 {
+				return nil, errors.ErrRewriteStepsExhausted
+			}
+			rewriter.remaining--
+			return rewriter.rewrite(rewritten)
+		}
+	}
+	return current, nil
+}
+
+
+// Rewrite applies the rules bottom-up (i.e. inner expressions are rewritten
+// before the outer ones), in the given order of priority, until no rule can
+// be applied anymore. At most maxSteps rules are applied: rule sets that do not
+// reach a fixed point by then (e.g. rules undoing each other) will make this
+// function return ErrRewriteStepsExhausted. The result is not simplified.
+func Rewrite(expression Expression, rules []RewriteRule, maxSteps int) (Expression, error) {
+	return (&rewriter{rules, maxSteps}).rewrite(expression)
+}
+
+
+// mustRewriteRule creates a rule known to be valid.
+func mustRewriteRule(
+	name string, pattern, template Expression, wildcards []Variable, guards ...RewriteGuard,
+) RewriteRule {
+	if rule, err := NewRewriteRule(name, pattern, template, wildcards, guards...); err != nil {
+		panic(err)
+	} else {
+		return rule
+	}
+}
+
+
+// DefaultRewriteRules returns the standard logarithm, exponential and power
+// identities, all of them oriented to make the expressions simpler:
+// - ln(exp(a)) = a.
+// - exp(ln(a)) = a.
+// - exp(a) * exp(b) = exp(a + b).
+// - exp(a)^b = exp(a * b).
+// - exp(b * ln(a)) = a^b.
+// - (a^b)^c = a^(b * c), for integer c.
+// - log(a, a) = 1.
+// - log(a, a^b) = b.
+// - a^log(a, b) = b.
+// Like the logarithms, they are meant for the positive arguments the logarithms
+// are defined for (e.g. exp(ln(a)) is undefined for a <= 0, while a is not).
+func DefaultRewriteRules() []RewriteRule {
+	a, b, c := Var("a"), Var("b"), Var("c")
+	wildcards := []Variable{a, b, c}
+	return []RewriteRule{
+		mustRewriteRule("ln-exp", Ln(Exp(a)), a, wildcards),
+		mustRewriteRule("exp-ln", Exp(Ln(a)), a, wildcards),
+		mustRewriteRule("exp-product", Mul(Exp(a), Exp(b)), Exp(Add(a, b)), wildcards),
+		mustRewriteRule("exp-power", Pow(Exp(a), b), Exp(Mul(a, b)), wildcards),
+		mustRewriteRule("exp-ln-product", Exp(Mul(b, Ln(a))), Pow(a, b), wildcards),
+		mustRewriteRule("power-power", Pow(Pow(a, b), c), Pow(a, Mul(b, c)), wildcards, NumericGuard(c, sets.Z)),
+		mustRewriteRule("log-same", Log(a, a), Num(1), wildcards),
+		mustRewriteRule("log-power", Log(a, Pow(a, b)), b, wildcards),
+		mustRewriteRule("power-log", Pow(a, Log(a, b)), b, wildcards),
+	}
+}
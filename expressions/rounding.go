@@ -23,6 +23,15 @@ func (round Round) Curry(arguments Arguments) (Expression, error) {
 }
 
 
+func (round Round) Substitute(substitutions Substitutions) (Expression, error) {
+	if substituted, err := round.arg.Substitute(substitutions); err != nil {
+		return nil, err
+	} else {
+		return Round{substituted, round.roundType}, nil
+	}
+}
+
+
 func (round Round) CollectVariables(variables Variables) {
 	round.arg.CollectVariables(variables)
 }
@@ -93,6 +102,15 @@ func (frac Frac) Curry(arguments Arguments) (Expression, error) {
 }
 
 
+func (frac Frac) Substitute(substitutions Substitutions) (Expression, error) {
+	if substituted, err := frac.arg.Substitute(substitutions); err != nil {
+		return nil, err
+	} else {
+		return Frac{substituted}, nil
+	}
+}
+
+
 func (frac Frac) CollectVariables(variables Variables) {
 	frac.arg.CollectVariables(variables)
 }
@@ -166,6 +184,15 @@ func (defectiveOnInt DefectiveOnInt) Curry(arguments Arguments) (Expression, err
 }
 
 
+func (defectiveOnInt DefectiveOnInt) Substitute(substitutions Substitutions) (Expression, error) {
+	if substituted, err := defectiveOnInt.bypassed.Substitute(substitutions); err != nil {
+		return nil, err
+	} else {
+		return DefectiveOnInt{substituted, defectiveOnInt.result}, nil
+	}
+}
+
+
 func (defectiveOnInt DefectiveOnInt) CollectVariables(variables Variables) {
 	defectiveOnInt.bypassed.CollectVariables(variables)
 }
@@ -0,0 +1,72 @@
+package expressions
+
+import (
+	"math/big"
+	"github.com/universe-10th/calculus/errors"
+	"github.com/universe-10th/calculus/ops"
+)
+
+
+// seriesTerm computes the k-th term of a series: f(k)(a) / k! * (X - a)^k, given
+// the k-th derivative of the expanded expression.
+func seriesTerm(derivative Expression, wrt Variable, around Constant, k int) (Expression, error) {
+	if atPoint, err := derivative.Curry(Arguments{wrt: around.number}); err != nil {
+		return nil, err
+	} else {
+		displacement := Expression(wrt)
+		if !ops.IsZero(around.number) {
+			displacement = Sub(wrt, around)
+		}
+		factorial := new(big.Int).MulRange(1, int64(k))
+		if k == 0 {
+			factorial.SetInt64(1)
+		}
+		return Mul(Div(atPoint, Constant{factorial}), Pow(displacement, Num(k))).Simplify()
+	}
+}
+
+
+// Series computes the Taylor polynomial, of the given order, of an expression
+// on the given variable, around the given (numeric) point:
+//     f(a) + f'(a) * (X - a) + f''(a) / 2! * (X - a)^2 + ... + f(n)(a) / n! * (X - a)^n.
+// Other variables are kept in the coefficients. Alongside the polynomial, an
+// estimate of the error is returned: the first omitted term, f(n+1)(a) / (n+1)! *
+// (X - a)^(n+1) (an error estimate valid near the point, not a bound of the
+// error). The expression must be derivable enough times (so, e.g.,
+// goal-seeks on the variable cannot be expanded) and each derivative must be
+// defined at the point. Otherwise, the corresponding error is returned.
+func Series(expression Expression, wrt Variable, around interface{}, order int) (Expression, Expression, error) {
+	if order < 0 {
+		return nil, nil, errors.ErrNegativeSeriesOrder
+	}
+	point := Num(around)
+	if point.number == nil {
+		return nil, nil, errors.ErrInvalidArgument
+	}
+	terms := []Expression{}
+	derivative := expression
+	for k := 0; ; k++ {
+		term, err := seriesTerm(derivative, wrt, point, k)
+		if err != nil {
+			return nil, nil, err
+		}
+		if k > order {
+			if polynomial, err := Add(terms...).Simplify(); err != nil {
+				return nil, nil, err
+			} else {
+				return polynomial, term, nil
+			}
+		}
+		terms = append(terms, term)
+		if derivative, err = derivative.Derivative(wrt); err != nil {
+			return nil, nil, err
+		}
+	}
+}
+
+
+// Maclaurin computes the Taylor polynomial, of the given order, of an expression
+// on the given variable, around 0. See Series for more details.
+func Maclaurin(expression Expression, wrt Variable, order int) (Expression, Expression, error) {
+	return Series(expression, wrt, 0, order)
+}
@@ -0,0 +1,137 @@
+package expressions
+
+import (
+	"github.com/universe-10th/calculus/errors"
+)
+
+
+// nodeArguments returns the inner expressions of a node, in order.
+func nodeArguments(expression Expression) []Expression {
+	switch node := expression.(type) {
+	case Constant, Variable:
+		return nil
+	case AddExpr:
+		return node.terms
+	case MulExpr:
+		return node.factors
+	case NegatedExpr:
+		return []Expression{node.arg}
+	case InverseExpr:
+		return []Expression{node.arg}
+	case PowExpr:
+		return []Expression{node.base, node.exponent}
+	case FactorialExpr:
+		return []Expression{node.arg}
+	case Round:
+		return []Expression{node.arg}
+	case Frac:
+		return []Expression{node.arg}
+	case DefectiveOnInt:
+		return []Expression{node.bypassed}
+	case DefectiveOnZero:
+		return []Expression{node.bypassed}
+	case GoalSeekExpr:
+		return []Expression{node.goal, node.target}
+	case IntegralExpr:
+		return []Expression{node.integrand, node.lower, node.upper}
+	case Function:
+		return node.Arguments()
+	default:
+		return nil
+	}
+}
+
+
+// withArguments builds a node of the same kind of the given one, but with the
+// given inner expressions instead (in the same order nodeArguments returns them).
+// No simplification is done here. Custom functions are rebuilt by their registered
+// builders, and an error is returned if they are not registered.
+func withArguments(expression Expression, arguments []Expression) (Expression, error) {
+	switch node := expression.(type) {
+	case Constant, Variable:
+		return node, nil
+	case AddExpr:
+		return Add(arguments...), nil
+	case MulExpr:
+		return Mul(arguments...), nil
+	case NegatedExpr:
+		return NegatedExpr{arguments[0]}, nil
+	case InverseExpr:
+		return InverseExpr{arguments[0]}, nil
+	case PowExpr:
+		return PowExpr{arguments[0], arguments[1]}, nil
+	case FactorialExpr:
+		return FactorialExpr{arguments[0]}, nil
+	case Round:
+		return Round{arguments[0], node.roundType}, nil
+	case Frac:
+		return Frac{arguments[0]}, nil
+	case DefectiveOnInt:
+		return DefectiveOnInt{arguments[0], node.result}, nil
+	case DefectiveOnZero:
+		return DefectiveOnZero{arguments[0], node.result}, nil
+	case GoalSeekExpr:
+		return node.rebuild(arguments[0], arguments[1]), nil
+	case IntegralExpr:
+		return node.rebuild(arguments[0], arguments[1], arguments[2]), nil
+	case LnExpr:
+		return Ln(arguments[0]), nil
+	case LogExpr:
+		return Log(arguments[0], arguments[1]), nil
+	case ExpExpr:
+		return Exp(arguments[0]), nil
+	case SinExpr:
+		return Sin(arguments[0]), nil
+	case CosExpr:
+		return Cos(arguments[0]), nil
+	case TanExpr:
+		return Tan(arguments[0]), nil
+	case Function:
+		if builder, ok := LookupFunction(node.StandardName()); !ok {
+			return nil, errors.ErrUnregisteredFunction
+		} else {
+			return builder(arguments...)
+		}
+	default:
+		return node, nil
+	}
+}
+
+
+// mapArguments rebuilds a node after transforming each of its inner expressions.
+func mapArguments(expression Expression, transform func(Expression) (Expression, error)) (Expression, error) {
+	arguments := nodeArguments(expression)
+	if len(arguments) == 0 {
+		return expression, nil
+	}
+	transformed := make([]Expression, len(arguments))
+	for index, argument := range arguments {
+		if result, err := transform(argument); err != nil {
+			return nil, err
+		} else {
+			transformed[index] = result
+		}
+	}
+	return withArguments(expression, transformed)
+}
+
+
+// boundSubstitutions returns the substitutions to apply in the scope of a bound
+// variable (e.g. the inverted variable of a goal-seek): all of them, but the one
+// for the bound variable itself. It fails with ErrCapturedVariable if any of the
+// substitutions applying to the scope involves the bound variable.
+func boundSubstitutions(substitutions Substitutions, bound Variable, scope Expression) (Substitutions, error) {
+	scopeVariables := Variables{}
+	scope.CollectVariables(scopeVariables)
+	result := Substitutions{}
+	for variable, substitution := range substitutions {
+		if variable == bound || !scopeVariables[variable] {
+			continue
+		} else if !substitution.IsConstant(bound) {
+			return nil, errors.ErrCapturedVariable
+		} else {
+			result[variable] = substitution
+		}
+	}
+	return result, nil
+}
@@ -81,6 +81,16 @@ func (sin SinExpr) Curry(args Arguments) (Expression, error) {
 }
 
 
+// Substitute replaces the variables in the underlying expression, and rebuilds the node.
+func (sin SinExpr) Substitute(substitutions Substitutions) (Expression, error) {
+	if substituted, err := sin.arg.Substitute(substitutions); err != nil {
+		return nil, err
+	} else {
+		return Sin(substituted), nil
+	}
+}
+
+
 // Evaluate computes the sine expression by first computing the inner expression, and then applying the sine.
 func (sin SinExpr) Evaluate(args Arguments) (sets.Number, error) {
 	if result, err := sin.arg.Evaluate(args); err == nil {
@@ -119,7 +129,17 @@ func (cos CosExpr) Curry(args Arguments) (Expression, error) {
 	if curried, err := cos.arg.Curry(args); err != nil {
 		return nil, err
 	} else {
-		return Sin(curried).Simplify()
+		return Cos(curried).Simplify()
+	}
+}
+
+
+// Substitute replaces the variables in the underlying expression, and rebuilds the node.
+func (cos CosExpr) Substitute(substitutions Substitutions) (Expression, error) {
+	if substituted, err := cos.arg.Substitute(substitutions); err != nil {
+		return nil, err
+	} else {
+		return Cos(substituted), nil
 	}
 }
 
@@ -178,7 +198,17 @@ func (tan TanExpr) Curry(args Arguments) (Expression, error) {
 	if curried, err := tan.arg.Curry(args); err != nil {
 		return nil, err
 	} else {
-		return Sin(curried).Simplify()
+		return Tan(curried).Simplify()
+	}
+}
+
+
+// Substitute replaces the variables in the underlying expression, and rebuilds the node.
+func (tan TanExpr) Substitute(substitutions Substitutions) (Expression, error) {
+	if substituted, err := tan.arg.Substitute(substitutions); err != nil {
+		return nil, err
+	} else {
+		return Tan(substituted), nil
 	}
 }
 
@@ -193,12 +223,13 @@ func (tan TanExpr) Evaluate(args Arguments) (sets.Number, error) {
 }
 
 
-// Derivative uses the tangent rule (actually, the derivative of sin(x)/cos(x)) and also applies the chain rule.
+// Derivative uses the tangent rule (actually, the derivative of sin(x)/cos(x), which is 1/cos(x)^2)
+// and also applies the chain rule.
 func (tan TanExpr) Derivative(wrt Variable) (Expression, error) {
 	if derivative, err := tan.arg.Derivative(wrt); err != nil {
 		return nil, err
 	} else {
-		return Mul(Pow(Sin(tan.arg), Num(-2)), derivative).Simplify()
+		return Mul(Pow(Cos(tan.arg), Num(-2)), derivative).Simplify()
 	}
 }
 
@@ -219,3 +250,227 @@ func Cos(arg Expression) Expression {
 func Tan(arg Expression) Expression {
 	return TanExpr{TrigFunctionExpr{FunctionExpr{"tan"},arg}}
 }
+
+
+// wrappedTrig computes a trigonometric function which may be undefined for the
+// input, converting the panic into the given error.
+func wrappedTrig(f func(sets.Number) sets.Number, input sets.Number, undefined error) (result sets.Number, err error) {
+	defer func(){
+		if r := recover(); r != nil {
+			result = nil
+			err = undefined
+		}
+	}()
+	result = f(input)
+	return
+}
+
+
+// SecExpr stands for a secant expression.
+type SecExpr struct {
+	TrigFunctionExpr
+}
+
+
+// CscExpr stands for a cosecant expression.
+type CscExpr struct {
+	TrigFunctionExpr
+}
+
+
+// CotExpr stands for a cotangent expression.
+type CotExpr struct {
+	TrigFunctionExpr
+}
+
+
+// Simplify attempts reducing a secant expression to a constant.
+// It is an error if the constant's cosine is 0.
+func (sec SecExpr) Simplify() (Expression, error) {
+	if simplified, err := sec.arg.Simplify(); err != nil {
+		return nil, err
+	} else if num, ok := simplified.(Constant); ok {
+		if result, err := wrappedTrig(ops.Sec, num.number, errors.ErrReciprocalTrigOfZero); err != nil {
+			return nil, err
+		} else {
+			return Constant{result}, nil
+		}
+	} else {
+		return Sec(simplified), nil
+	}
+}
+
+
+// Curry tries currying the underlying expression first, and then attempts simplifying.
+func (sec SecExpr) Curry(args Arguments) (Expression, error) {
+	if curried, err := sec.arg.Curry(args); err != nil {
+		return nil, err
+	} else {
+		return Sec(curried).Simplify()
+	}
+}
+
+
+// Substitute replaces the variables in the underlying expression, and rebuilds the node.
+func (sec SecExpr) Substitute(substitutions Substitutions) (Expression, error) {
+	if substituted, err := sec.arg.Substitute(substitutions); err != nil {
+		return nil, err
+	} else {
+		return Sec(substituted), nil
+	}
+}
+
+
+// Evaluate computes the secant over the evaluated value of the inner expression.
+// It is an error if the cosine of the value is 0.
+func (sec SecExpr) Evaluate(args Arguments) (sets.Number, error) {
+	if result, err := sec.arg.Evaluate(args); err == nil {
+		return wrappedTrig(ops.Sec, result, errors.ErrReciprocalTrigOfZero)
+	} else {
+		return nil, err
+	}
+}
+
+
+// Derivative uses the secant rule (sec(x) * tan(x)) and also applies the chain rule.
+func (sec SecExpr) Derivative(wrt Variable) (Expression, error) {
+	if derivative, err := sec.arg.Derivative(wrt); err != nil {
+		return nil, err
+	} else {
+		return Mul(Sec(sec.arg), Tan(sec.arg), derivative).Simplify()
+	}
+}
+
+
+// Simplify attempts reducing a cosecant expression to a constant.
+// It is an error if the constant's sine is 0.
+func (csc CscExpr) Simplify() (Expression, error) {
+	if simplified, err := csc.arg.Simplify(); err != nil {
+		return nil, err
+	} else if num, ok := simplified.(Constant); ok {
+		if result, err := wrappedTrig(ops.Csc, num.number, errors.ErrReciprocalTrigOfZero); err != nil {
+			return nil, err
+		} else {
+			return Constant{result}, nil
+		}
+	} else {
+		return Csc(simplified), nil
+	}
+}
+
+
+// Curry tries currying the underlying expression first, and then attempts simplifying.
+func (csc CscExpr) Curry(args Arguments) (Expression, error) {
+	if curried, err := csc.arg.Curry(args); err != nil {
+		return nil, err
+	} else {
+		return Csc(curried).Simplify()
+	}
+}
+
+
+// Substitute replaces the variables in the underlying expression, and rebuilds the node.
+func (csc CscExpr) Substitute(substitutions Substitutions) (Expression, error) {
+	if substituted, err := csc.arg.Substitute(substitutions); err != nil {
+		return nil, err
+	} else {
+		return Csc(substituted), nil
+	}
+}
+
+
+// Evaluate computes the cosecant over the evaluated value of the inner expression.
+// It is an error if the sine of the value is 0.
+func (csc CscExpr) Evaluate(args Arguments) (sets.Number, error) {
+	if result, err := csc.arg.Evaluate(args); err == nil {
+		return wrappedTrig(ops.Csc, result, errors.ErrReciprocalTrigOfZero)
+	} else {
+		return nil, err
+	}
+}
+
+
+// Derivative uses the cosecant rule (-csc(x) * cot(x)) and also applies the chain rule.
+func (csc CscExpr) Derivative(wrt Variable) (Expression, error) {
+	if derivative, err := csc.arg.Derivative(wrt); err != nil {
+		return nil, err
+	} else {
+		return Negated(Mul(Csc(csc.arg), Cot(csc.arg), derivative)).Simplify()
+	}
+}
+
+
+// Simplify attempts reducing a cotangent expression to a constant.
+// It is an error if the constant's tangent is 0.
+func (cot CotExpr) Simplify() (Expression, error) {
+	if simplified, err := cot.arg.Simplify(); err != nil {
+		return nil, err
+	} else if num, ok := simplified.(Constant); ok {
+		if result, err := wrappedTrig(ops.Cot, num.number, errors.ErrReciprocalTrigOfZero); err != nil {
+			return nil, err
+		} else {
+			return Constant{result}, nil
+		}
+	} else {
+		return Cot(simplified), nil
+	}
+}
+
+
+// Curry tries currying the underlying expression first, and then attempts simplifying.
+func (cot CotExpr) Curry(args Arguments) (Expression, error) {
+	if curried, err := cot.arg.Curry(args); err != nil {
+		return nil, err
+	} else {
+		return Cot(curried).Simplify()
+	}
+}
+
+
+// Substitute replaces the variables in the underlying expression, and rebuilds the node.
+func (cot CotExpr) Substitute(substitutions Substitutions) (Expression, error) {
+	if substituted, err := cot.arg.Substitute(substitutions); err != nil {
+		return nil, err
+	} else {
+		return Cot(substituted), nil
+	}
+}
+
+
+// Evaluate computes the cotangent over the evaluated value of the inner expression.
+// It is an error if the tangent of the value is 0.
+func (cot CotExpr) Evaluate(args Arguments) (sets.Number, error) {
+	if result, err := cot.arg.Evaluate(args); err == nil {
+		return wrappedTrig(ops.Cot, result, errors.ErrReciprocalTrigOfZero)
+	} else {
+		return nil, err
+	}
+}
+
+
+// Derivative uses the cotangent rule (-csc(x)^2) and also applies the chain rule.
+func (cot CotExpr) Derivative(wrt Variable) (Expression, error) {
+	if derivative, err := cot.arg.Derivative(wrt); err != nil {
+		return nil, err
+	} else {
+		return Negated(Mul(Pow(Csc(cot.arg), Num(2)), derivative)).Simplify()
+	}
+}
+
+
+// Sec constructs a secant expression.
+func Sec(arg Expression) Expression {
+	return SecExpr{TrigFunctionExpr{FunctionExpr{"sec"},arg}}
+}
+
+
+// Csc constructs a cosecant expression.
+func Csc(arg Expression) Expression {
+	return CscExpr{TrigFunctionExpr{FunctionExpr{"csc"},arg}}
+}
+
+
+// Cot constructs a cotangent expression.
+func Cot(arg Expression) Expression {
+	return CotExpr{TrigFunctionExpr{FunctionExpr{"cot"},arg}}
+}
@@ -0,0 +1,429 @@
+package intervals
+
+import (
+	"math"
+	"math/big"
+	"github.com/ALTree/bigfloat"
+	"github.com/universe-10th/calculus/errors"
+	"github.com/universe-10th/calculus/ops"
+)
+
+
+// widen moves a transcendental result a few ulps outward (downward for lower
+// bounds), since those functions are not correctly rounded.
+func widen(value *big.Float, lower bool) *big.Float {
+	if value.IsInf() {
+		return value
+	}
+	bits := value.Prec()
+	ulp := new(big.Float).SetMantExp(big.NewFloat(1), value.MantExp(nil) - int(bits) + 2)
+	if value.Sign() == 0 {
+		ulp.SetMantExp(big.NewFloat(1), -int(bits))
+	}
+	if lower {
+		return newFloat(bits, big.ToNegativeInf).Sub(value, ulp)
+	}
+	return newFloat(bits, big.ToPositiveInf).Add(value, ulp)
+}
+
+
+// expBound computes the exponential of a bound.
+func expBound(x *big.Float, bits uint, lower bool) *big.Float {
+	if x.IsInf() {
+		if x.Sign() < 0 {
+			return newFloat(bits, big.ToNegativeInf)
+		}
+		return newFloat(bits, big.ToPositiveInf).SetInf(false)
+	}
+	result := widen(bigfloat.Exp(new(big.Float).SetPrec(bits).Set(x)), lower)
+	if result.Sign() < 0 {
+		result.SetInt64(0)
+	}
+	return result
+}
+
+
+// lnBound computes the natural logarithm of a non-negative bound.
+func lnBound(x *big.Float, bits uint, lower bool) *big.Float {
+	if x.Sign() == 0 {
+		return newFloat(bits, big.ToNegativeInf).SetInf(true)
+	} else if x.IsInf() {
+		return newFloat(bits, big.ToPositiveInf).SetInf(false)
+	}
+	return widen(bigfloat.Log(new(big.Float).SetPrec(bits).Set(x)), lower)
+}
+
+
+// Exp computes the interval of the exponentials of the values of the interval.
+// Since the exponential is increasing, only the bounds are evaluated.
+func Exp(a Interval) Interval {
+	bits := precision(a)
+	return Interval{expBound(a.lower, bits, true), expBound(a.upper, bits, false)}
+}
+
+
+// Ln computes the interval of the natural logarithms of the values of the interval.
+// Since the logarithm is increasing, only the bounds are evaluated. It is an error
+// if the interval contains negative numbers. A 0 lower bound becomes -inf.
+func Ln(a Interval) (Interval, error) {
+	if a.lower.Sign() < 0 {
+		return Interval{}, errors.ErrLogarithmOfNegative
+	}
+	bits := precision(a)
+	return Interval{lnBound(a.lower, bits, true), lnBound(a.upper, bits, false)}, nil
+}
+
+
+// Log computes the interval of the logarithms of the values of the power interval
+// in the bases of the base interval. See Ln and Div for the error conditions.
+func Log(power, base Interval) (Interval, error) {
+	if lnPower, err := Ln(power); err != nil {
+		return Interval{}, err
+	} else if lnBase, err := Ln(base); err != nil {
+		return Interval{}, err
+	} else {
+		return Div(lnPower, lnBase)
+	}
+}
+
+
+// magnitudePow computes |x|^n for a positive integer n, rounding in the given mode.
+func magnitudePow(x *big.Float, n uint64, bits uint, mode big.RoundingMode) *big.Float {
+	factor := newFloat(bits, mode).Abs(x)
+	total := newFloat(bits, mode).SetInt64(1)
+	for n > 0 {
+		if n & 1 == 1 {
+			total.Mul(total, factor)
+		}
+		n >>= 1
+		if n > 0 {
+			factor.Mul(factor, factor)
+		}
+	}
+	return total
+}
+
+
+// intPowBound computes x^n for a positive integer n, rounding in the given mode.
+func intPowBound(x *big.Float, n uint64, bits uint, lower bool) *big.Float {
+	if x.Sign() < 0 && n & 1 == 1 {
+		// The power is negative, so the magnitude is rounded the other way.
+		mode := big.ToNegativeInf
+		if lower {
+			mode = big.ToPositiveInf
+		}
+		magnitude := magnitudePow(x, n, bits, mode)
+		return magnitude.Neg(magnitude)
+	} else if lower {
+		return magnitudePow(x, n, bits, big.ToNegativeInf)
+	} else {
+		return magnitudePow(x, n, bits, big.ToPositiveInf)
+	}
+}
+
+
+// intPow computes the interval of the n-th powers of the values of the interval,
+// taking into account that even powers are not monotonic.
+func intPow(a Interval, n uint64) Interval {
+	bits := precision(a)
+	if n & 1 == 1 || a.lower.Sign() >= 0 {
+		return Interval{intPowBound(a.lower, n, bits, true), intPowBound(a.upper, n, bits, false)}
+	} else if a.upper.Sign() <= 0 {
+		return Interval{intPowBound(a.upper, n, bits, true), intPowBound(a.lower, n, bits, false)}
+	} else {
+		upper := intPowBound(a.lower, n, bits, false)
+		if candidate := intPowBound(a.upper, n, bits, false); candidate.Cmp(upper) > 0 {
+			upper = candidate
+		}
+		return Interval{newFloat(bits, big.ToNegativeInf), upper}
+	}
+}
+
+
+// Pow computes the interval of the powers of the values of the base interval to
+// the values of the exponent interval. When the exponent is a single integer, any
+// base is allowed (and negative exponents behave like Inv). Otherwise, the base
+// must not contain negative numbers, and the power is computed as exp(y * ln(x)).
+func Pow(base, exponent Interval) (Interval, error) {
+	if exponent.IsPoint() && exponent.lower.IsInt() && !exponent.lower.IsInf() {
+		n, accuracy := exponent.lower.Int64()
+		if accuracy != big.Exact || n == math.MinInt64 {
+			return Interval{}, errors.ErrInvalidPowerOperation
+		}
+		switch {
+		case n == 0:
+			return Point(big.NewInt(1)), nil
+		case n > 0:
+			return intPow(base, uint64(n)), nil
+		default:
+			return Inv(intPow(base, uint64(-n)))
+		}
+	}
+	if base.lower.Sign() < 0 {
+		return Interval{}, errors.ErrInvalidPowerOperation
+	}
+	if lnBase, err := Ln(base); err != nil {
+		return Interval{}, err
+	} else {
+		return Exp(Mul(exponent, lnBase)), nil
+	}
+}
+
+
+// The slack given to float64-computed trigonometric values.
+const trigSlack = 1.0 / (1 << 50)
+
+
+// boundFloat converts a bound to float64, rounding it outward: downward for
+// lower bounds, and upward for upper bounds. This way, the float64 interval
+// always contains the original one.
+func boundFloat(x *big.Float, lower bool) float64 {
+	mode := big.ToPositiveInf
+	if lower {
+		mode = big.ToNegativeInf
+	}
+	// 53 bits is the precision of float64 numbers, so the conversion is exact.
+	value, _ := newFloat(53, mode).Set(x).Float64()
+	return value
+}
+
+
+// trigBounds converts the interval bounds to float64 (rounding them outward) for
+// the trigonometric functions, telling whether the conversion is meaningful (the
+// bounds are finite and small enough for the period to be distinguishable).
+func trigBounds(a Interval) (float64, float64, bool) {
+	lower := boundFloat(a.lower, true)
+	upper := boundFloat(a.upper, false)
+	limit := float64(1 << 52)
+	if math.IsInf(lower, 0) || math.IsInf(upper, 0) || math.Abs(lower) > limit || math.Abs(upper) > limit {
+		return 0, 0, false
+	}
+	return lower, upper, true
+}
+
+
+// reaches tells whether the [lower, upper] interval contains (or is too close
+// to tell) any of the points: offset + k * period.
+func reaches(lower, upper, offset, period float64) bool {
+	k := math.Ceil((lower - offset) / period - trigSlack)
+	return offset + k * period <= upper + trigSlack * (1 + math.Abs(upper))
+}
+
+
+// unitRange builds a trigonometric result, widened and clamped to [-1, 1].
+func unitRange(lower, upper float64, reachesMin, reachesMax bool) Interval {
+	if reachesMin {
+		lower = -1
+	} else {
+		lower = math.Max(-1, lower - trigSlack)
+	}
+	if reachesMax {
+		upper = 1
+	} else {
+		upper = math.Min(1, upper + trigSlack)
+	}
+	return Interval{newFloat(minPrecision, big.ToNegativeInf).SetFloat64(lower), newFloat(minPrecision, big.ToPositiveInf).SetFloat64(upper)}
+}
+
+
+// Sin computes the interval of the sines of the values of the interval: the
+// range of the bounds, extended to -1 or 1 when the interval contains a
+// minimum or maximum of the function. Computations are done in float64.
+func Sin(a Interval) Interval {
+	lower, upper, ok := trigBounds(a)
+	if !ok || upper - lower >= 2 * math.Pi {
+		return unitRange(-1, 1, true, true)
+	}
+	sinLower, sinUpper := math.Sin(lower), math.Sin(upper)
+	return unitRange(
+		math.Min(sinLower, sinUpper), math.Max(sinLower, sinUpper),
+		reaches(lower, upper, -math.Pi / 2, 2 * math.Pi), reaches(lower, upper, math.Pi / 2, 2 * math.Pi),
+	)
+}
+
+
+// Cos computes the interval of the cosines of the values of the interval: the
+// range of the bounds, extended to -1 or 1 when the interval contains a
+// minimum or maximum of the function. Computations are done in float64.
+func Cos(a Interval) Interval {
+	lower, upper, ok := trigBounds(a)
+	if !ok || upper - lower >= 2 * math.Pi {
+		return unitRange(-1, 1, true, true)
+	}
+	cosLower, cosUpper := math.Cos(lower), math.Cos(upper)
+	return unitRange(
+		math.Min(cosLower, cosUpper), math.Max(cosLower, cosUpper),
+		reaches(lower, upper, math.Pi, 2 * math.Pi), reaches(lower, upper, 0, 2 * math.Pi),
+	)
+}
+
+
+// Tan computes the interval of the tangents of the values of the interval. The
+// tangent is increasing between its poles, so only the bounds are evaluated,
+// unless the interval contains a pole: the tangents are unbounded and the entire
+// interval is returned. Computations are done in float64.
+func Tan(a Interval) Interval {
+	lower, upper, ok := trigBounds(a)
+	if !ok || upper - lower >= math.Pi || reaches(lower, upper, math.Pi / 2, math.Pi) {
+		return Entire()
+	}
+	tanLower, tanUpper := math.Tan(lower), math.Tan(upper)
+	return Interval{
+		newFloat(minPrecision, big.ToNegativeInf).SetFloat64(tanLower - trigSlack * (1 + math.Abs(tanLower))),
+		newFloat(minPrecision, big.ToPositiveInf).SetFloat64(tanUpper + trigSlack * (1 + math.Abs(tanUpper))),
+	}
+}
+
+
+// roundBound rounds a bound, keeping infinite bounds unchanged.
+func roundBound(x *big.Float, roundType ops.RoundType, mode big.RoundingMode) *big.Float {
+	if x.IsInf() {
+		return newFloat(x.Prec(), mode).Set(x)
+	}
+	rounded := ops.Round(x, roundType)
+	return newFloat(x.Prec(), mode).SetInt(rounded)
+}
+
+
+// Round computes the interval of the rounded values of the interval. Rounding
+// is non-decreasing for all the round types, so only the bounds are rounded.
+func Round(a Interval, roundType ops.RoundType) Interval {
+	return Interval{roundBound(a.lower, roundType, big.ToNegativeInf), roundBound(a.upper, roundType, big.ToPositiveInf)}
+}
+
+
+// Frac computes the interval of the fractional parts (x - trunc(x)) of the values
+// of the interval. If all the values share the same integer part, the fractional
+// part is x minus that part. Otherwise, the result is [0, 1], [-1, 0] or [-1, 1]
+// depending on the sign of the values.
+func Frac(a Interval) Interval {
+	bits := precision(a)
+	if !a.lower.IsInf() && !a.upper.IsInf() {
+		lowerPart := ops.Round(a.lower, ops.Inward)
+		upperPart := ops.Round(a.upper, ops.Inward)
+		if lowerPart.Cmp(upperPart) == 0 {
+			part := new(big.Float).SetInt(lowerPart)
+			return Interval{
+				newFloat(bits, big.ToNegativeInf).Sub(a.lower, part),
+				newFloat(bits, big.ToPositiveInf).Sub(a.upper, part),
+			}
+		}
+	}
+	lower := newFloat(bits, big.ToNegativeInf)
+	upper := newFloat(bits, big.ToPositiveInf)
+	if a.lower.Sign() < 0 {
+		lower.SetInt64(-1)
+	}
+	if a.upper.Sign() > 0 {
+		upper.SetInt64(1)
+	}
+	return Interval{lower, upper}
+}
+
+
+// Factorial computes the interval of the factorials of the natural numbers in
+// the interval. It is an error if the interval contains negative numbers, or
+// if it contains no natural number at all.
+func Factorial(a Interval) (result Interval, err error) {
+	if a.lower.Sign() < 0 || a.upper.IsInf() {
+		return Interval{}, errors.ErrInvalidFactorialArgument
+	}
+	lower := ops.Round(a.lower, ops.Ceil)
+	upper := ops.Round(a.upper, ops.Floor)
+	if lower.Cmp(upper) > 0 {
+		return Interval{}, errors.ErrInvalidFactorialArgument
+	}
+	defer func(){
+		if r := recover(); r != nil {
+			result = Interval{}
+			err = errors.ErrInvalidFactorialArgument
+		}
+	}()
+	bits := precision(a)
+	return Interval{
+		newFloat(bits, big.ToNegativeInf).SetInt(ops.Factorial(lower).(*big.Int)),
+		newFloat(bits, big.ToPositiveInf).SetInt(ops.Factorial(upper).(*big.Int)),
+	}, nil
+}
+
+
+// ContainsInteger tells whether the interval contains at least one integer.
+func ContainsInteger(a Interval) bool {
+	if a.lower.IsInf() || a.upper.IsInf() {
+		return true
+	}
+	return new(big.Float).SetInt(ops.Round(a.lower, ops.Ceil)).Cmp(a.upper) <= 0
+}
+
+
+// floatInterval builds an interval out of float64 bounds, widened by the trigonometric slack.
+func floatInterval(lower, upper float64) Interval {
+	return Interval{
+		newFloat(minPrecision, big.ToNegativeInf).SetFloat64(lower - trigSlack * (1 + math.Abs(lower))),
+		newFloat(minPrecision, big.ToPositiveInf).SetFloat64(upper + trigSlack * (1 + math.Abs(upper))),
+	}
+}
+
+
+// ArcSin computes the interval of the arc sines of the values of the interval.
+// It is an error if the interval is not contained in [-1, 1].
+func ArcSin(a Interval) (Interval, error) {
+	if a.lower.Cmp(big.NewFloat(-1)) < 0 || a.upper.Cmp(big.NewFloat(1)) > 0 {
+		return Interval{}, errors.ErrArcOutOfDomain
+	}
+	return floatInterval(math.Asin(boundFloat(a.lower, true)), math.Asin(boundFloat(a.upper, false))), nil
+}
+
+
+// ArcCos computes the interval of the arc cosines of the values of the interval.
+// It is an error if the interval is not contained in [-1, 1].
+func ArcCos(a Interval) (Interval, error) {
+	if a.lower.Cmp(big.NewFloat(-1)) < 0 || a.upper.Cmp(big.NewFloat(1)) > 0 {
+		return Interval{}, errors.ErrArcOutOfDomain
+	}
+	return floatInterval(math.Acos(boundFloat(a.upper, false)), math.Acos(boundFloat(a.lower, true))), nil
+}
+
+
+// ArcTan computes the interval of the arc tangents of the values of the interval.
+// Since the arc tangent is increasing, only the bounds are evaluated.
+func ArcTan(a Interval) Interval {
+	return floatInterval(math.Atan(boundFloat(a.lower, true)), math.Atan(boundFloat(a.upper, false)))
+}
+
+
+// ArcTan2 computes the interval of the angles of the points in the box given by
+// both intervals. When the box crosses the negative x axis, the angles may be
+// anywhere in [-pi, pi]. It is an error if the box is just the origin.
+func ArcTan2(y, x Interval) (Interval, error) {
+	zero := big.NewFloat(0)
+	switch {
+	case x.lower.Sign() > 0:
+		// The angle is arctan(y / x).
+		quotient, err := Div(y, x)
+		if err != nil {
+			return Interval{}, err
+		}
+		return ArcTan(quotient), nil
+	case y.lower.Sign() > 0:
+		// The angle is pi/2 - arctan(x / y).
+		quotient, err := Div(x, y)
+		if err != nil {
+			return Interval{}, err
+		}
+		arc := ArcTan(quotient)
+		return floatInterval(math.Pi / 2 - boundFloat(arc.upper, false), math.Pi / 2 - boundFloat(arc.lower, true)), nil
+	case y.upper.Sign() < 0:
+		// The angle is -pi/2 - arctan(x / y).
+		quotient, err := Div(x, y)
+		if err != nil {
+			return Interval{}, err
+		}
+		arc := ArcTan(quotient)
+		return floatInterval(-math.Pi / 2 - boundFloat(arc.upper, false), -math.Pi / 2 - boundFloat(arc.lower, true)), nil
+	case x.IsPoint() && y.IsPoint() && x.lower.Cmp(zero) == 0 && y.lower.Cmp(zero) == 0:
+		return Interval{}, errors.ErrAngleOfOrigin
+	default:
+		return floatInterval(-math.Pi, math.Pi), nil
+	}
+}
@@ -0,0 +1,120 @@
+package intervals
+
+import (
+	"math/big"
+	"github.com/universe-10th/calculus/errors"
+	"github.com/universe-10th/calculus/ops"
+	"github.com/universe-10th/calculus/sets"
+)
+
+
+// hyperbolicBound computes an increasing hyperbolic function (or inverse) of a
+// bound, with the precision of the interval. Infinite bounds are kept, or turn
+// into the given limit (if not nil).
+func hyperbolicBound(f func(sets.Number) sets.Number, x *big.Float, bits uint, lower bool, limit *big.Float) *big.Float {
+	if x.IsInf() {
+		if limit == nil {
+			return newFloat(bits, big.ToNearestEven).SetInf(x.Sign() < 0)
+		} else if x.Sign() < 0 {
+			return newFloat(bits, big.ToNearestEven).Neg(limit)
+		}
+		return newFloat(bits, big.ToNearestEven).Set(limit)
+	}
+	return widen(f(new(big.Float).SetPrec(bits).Set(x)).(*big.Float), lower)
+}
+
+
+// clampBound keeps a widened bound inside the range of a function.
+func clampBound(x *big.Float, minimum, maximum *big.Float) *big.Float {
+	if minimum != nil && x.Cmp(minimum) < 0 {
+		return x.Set(minimum)
+	} else if maximum != nil && x.Cmp(maximum) > 0 {
+		return x.Set(maximum)
+	}
+	return x
+}
+
+
+// Sinh computes the interval of the hyperbolic sines of the values of the interval.
+// Since the hyperbolic sine is increasing, only the bounds are evaluated.
+func Sinh(a Interval) Interval {
+	bits := precision(a)
+	return Interval{
+		hyperbolicBound(ops.Sinh, a.lower, bits, true, nil),
+		hyperbolicBound(ops.Sinh, a.upper, bits, false, nil),
+	}
+}
+
+
+// Cosh computes the interval of the hyperbolic cosines of the values of the
+// interval. The minimum, 1, is reached when the interval contains 0, and the
+// maximum is reached in the bound farthest from 0.
+func Cosh(a Interval) Interval {
+	bits := precision(a)
+	one := big.NewFloat(1)
+	magnitude := new(big.Float).Abs(a.lower)
+	if upper := new(big.Float).Abs(a.upper); upper.Cmp(magnitude) > 0 {
+		magnitude = upper
+	}
+	result := Interval{newFloat(bits, big.ToNegativeInf).SetInt64(1), hyperbolicBound(ops.Cosh, magnitude, bits, false, nil)}
+	if a.lower.Sign() > 0 {
+		result.lower = clampBound(hyperbolicBound(ops.Cosh, a.lower, bits, true, nil), one, nil)
+	} else if a.upper.Sign() < 0 {
+		result.lower = clampBound(hyperbolicBound(ops.Cosh, a.upper, bits, true, nil), one, nil)
+	}
+	return result
+}
+
+
+// Tanh computes the interval of the hyperbolic tangents of the values of the
+// interval. Since the hyperbolic tangent is increasing, only the bounds are
+// evaluated. The result is always contained in [-1, 1].
+func Tanh(a Interval) Interval {
+	bits := precision(a)
+	one := big.NewFloat(1)
+	minusOne := big.NewFloat(-1)
+	return Interval{
+		clampBound(hyperbolicBound(ops.Tanh, a.lower, bits, true, one), minusOne, one),
+		clampBound(hyperbolicBound(ops.Tanh, a.upper, bits, false, one), minusOne, one),
+	}
+}
+
+
+// ArcSinh computes the interval of the inverse hyperbolic sines of the values of
+// the interval. Since the inverse hyperbolic sine is increasing, only the bounds
+// are evaluated.
+func ArcSinh(a Interval) Interval {
+	bits := precision(a)
+	return Interval{
+		hyperbolicBound(ops.ArcSinh, a.lower, bits, true, nil),
+		hyperbolicBound(ops.ArcSinh, a.upper, bits, false, nil),
+	}
+}
+
+
+// ArcCosh computes the interval of the inverse hyperbolic cosines of the values
+// of the interval. It is an error if the interval contains numbers lower than 1.
+func ArcCosh(a Interval) (Interval, error) {
+	if a.lower.Cmp(big.NewFloat(1)) < 0 {
+		return Interval{}, errors.ErrArcHyperbolicOutOfDomain
+	}
+	bits := precision(a)
+	return Interval{
+		clampBound(hyperbolicBound(ops.ArcCosh, a.lower, bits, true, nil), big.NewFloat(0), nil),
+		hyperbolicBound(ops.ArcCosh, a.upper, bits, false, nil),
+	}, nil
+}
+
+
+// ArcTanh computes the interval of the inverse hyperbolic tangents of the values
+// of the interval. It is an error if the interval is not contained in (-1, 1).
+func ArcTanh(a Interval) (Interval, error) {
+	if a.lower.Cmp(big.NewFloat(-1)) <= 0 || a.upper.Cmp(big.NewFloat(1)) >= 0 {
+		return Interval{}, errors.ErrArcHyperbolicOutOfDomain
+	}
+	bits := precision(a)
+	return Interval{
+		hyperbolicBound(ops.ArcTanh, a.lower, bits, true, nil),
+		hyperbolicBound(ops.ArcTanh, a.upper, bits, false, nil),
+	}, nil
+}
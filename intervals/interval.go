@@ -0,0 +1,262 @@
+package intervals
+
+import (
+	"fmt"
+	"math/big"
+	"github.com/universe-10th/calculus/errors"
+	"github.com/universe-10th/calculus/sets"
+)
+
+
+// The minimum precision the bounds of an interval will have.
+const minPrecision = 53
+
+
+// Interval is a closed range of real numbers: [lower, upper]. Bounds may be
+// infinite (e.g. when dividing by an interval containing 0). Operations over
+// intervals round their bounds outward, so the results always enclose all the
+// values the operation can take on the given intervals.
+type Interval struct {
+	lower, upper *big.Float
+}
+
+
+// newFloat creates a float with the given precision and rounding mode.
+func newFloat(precision uint, mode big.RoundingMode) *big.Float {
+	return new(big.Float).SetPrec(precision).SetMode(mode)
+}
+
+
+// bound converts a number (or a primitive value, which is wrapped first) to a
+// float, rounding it in the given mode. Floats keep their precision (if larger
+// than the minimum one).
+func bound(number interface{}, mode big.RoundingMode) *big.Float {
+	wrapped, _ := sets.Wrap(number)
+	switch value := wrapped.(type) {
+	case *big.Int:
+		return newFloat(minPrecision, mode).SetInt(value)
+	case *big.Rat:
+		return newFloat(minPrecision, mode).SetRat(value)
+	case *big.Float:
+		precision := value.Prec()
+		if precision < minPrecision {
+			precision = minPrecision
+		}
+		return newFloat(precision, mode).Set(value)
+	default:
+		panic("cannot create an interval bound from a non-*big.(Int, Float, Rat) value")
+	}
+}
+
+
+// New creates an interval out of its bounds, which must be in order. Bounds
+// not representable as floats (e.g. 1/3) are rounded outward. Primitive
+// numbers are also allowed, like in expressions.Num.
+func New(lower, upper interface{}) (Interval, error) {
+	interval := Interval{bound(lower, big.ToNegativeInf), bound(upper, big.ToPositiveInf)}
+	if interval.lower.Cmp(interval.upper) > 0 {
+		return Interval{}, errors.ErrEmptyInterval
+	}
+	return interval, nil
+}
+
+
+// Point creates an interval containing only the given number (or, if it is
+// not representable as a float, the tightest interval around it). Primitive
+// numbers are also allowed, like in expressions.Num.
+func Point(number interface{}) Interval {
+	return Interval{bound(number, big.ToNegativeInf), bound(number, big.ToPositiveInf)}
+}
+
+
+// Entire creates the (-inf, +inf) interval.
+func Entire() Interval {
+	return Interval{
+		newFloat(minPrecision, big.ToNegativeInf).SetInf(true),
+		newFloat(minPrecision, big.ToPositiveInf).SetInf(false),
+	}
+}
+
+
+// Lower returns a copy of the lower bound.
+func (interval Interval) Lower() *big.Float {
+	return new(big.Float).Copy(interval.lower)
+}
+
+
+// Upper returns a copy of the upper bound.
+func (interval Interval) Upper() *big.Float {
+	return new(big.Float).Copy(interval.upper)
+}
+
+
+// IsPoint tells whether both bounds are the same.
+func (interval Interval) IsPoint() bool {
+	return interval.lower.Cmp(interval.upper) == 0
+}
+
+
+// Contains tells whether the number belongs to the interval.
+func (interval Interval) Contains(number sets.Number) bool {
+	value := sets.UpCastOneTo(number, sets.R).(*big.Float)
+	return interval.lower.Cmp(value) <= 0 && value.Cmp(interval.upper) <= 0
+}
+
+
+// precision tells the precision of the results of an operation over the given
+// intervals: the largest precision among their bounds.
+func precision(intervals ...Interval) uint {
+	result := uint(minPrecision)
+	for _, interval := range intervals {
+		if interval.lower.Prec() > result {
+			result = interval.lower.Prec()
+		}
+		if interval.upper.Prec() > result {
+			result = interval.upper.Prec()
+		}
+	}
+	return result
+}
+
+
+// sign tells whether the interval is entirely negative (-1), entirely positive
+// (1) or contains 0 (0), regarding its interior.
+func (interval Interval) sign() int {
+	if interval.upper.Sign() <= 0 && interval.lower.Sign() < 0 {
+		return -1
+	} else if interval.lower.Sign() >= 0 && interval.upper.Sign() > 0 {
+		return 1
+	}
+	return 0
+}
+
+
+func (interval Interval) String() string {
+	if interval.lower == nil {
+		return "[]"
+	}
+	return fmt.Sprintf("[%s, %s]", interval.lower.Text('g', -1), interval.upper.Text('g', -1))
+}
+
+
+// Add computes the interval of the sums of the values of both intervals.
+func Add(a, b Interval) Interval {
+	bits := precision(a, b)
+	return Interval{
+		newFloat(bits, big.ToNegativeInf).Add(a.lower, b.lower),
+		newFloat(bits, big.ToPositiveInf).Add(a.upper, b.upper),
+	}
+}
+
+
+// Neg computes the interval of the opposites of the values of the interval.
+func Neg(a Interval) Interval {
+	bits := precision(a)
+	return Interval{
+		newFloat(bits, big.ToNegativeInf).Neg(a.upper),
+		newFloat(bits, big.ToPositiveInf).Neg(a.lower),
+	}
+}
+
+
+// Sub computes the interval of the differences of the values of both intervals.
+func Sub(a, b Interval) Interval {
+	return Add(a, Neg(b))
+}
+
+
+// product multiplies two bounds in the given rounding mode, taking 0 * inf as 0.
+func product(x, y *big.Float, bits uint, mode big.RoundingMode) *big.Float {
+	if x.Sign() == 0 || y.Sign() == 0 {
+		return newFloat(bits, mode)
+	}
+	return newFloat(bits, mode).Mul(x, y)
+}
+
+
+// Mul computes the interval of the products of the values of both intervals.
+func Mul(a, b Interval) Interval {
+	bits := precision(a, b)
+	lower := product(a.lower, b.lower, bits, big.ToNegativeInf)
+	upper := product(a.lower, b.lower, bits, big.ToPositiveInf)
+	for _, pair := range [][2]*big.Float{{a.lower, b.upper}, {a.upper, b.lower}, {a.upper, b.upper}} {
+		if candidate := product(pair[0], pair[1], bits, big.ToNegativeInf); candidate.Cmp(lower) < 0 {
+			lower = candidate
+		}
+		if candidate := product(pair[0], pair[1], bits, big.ToPositiveInf); candidate.Cmp(upper) > 0 {
+			upper = candidate
+		}
+	}
+	return Interval{lower, upper}
+}
+
+
+// quotient divides 1 by a bound in the given rounding mode.
+func quotient(x *big.Float, bits uint, mode big.RoundingMode) *big.Float {
+	if x.IsInf() {
+		return newFloat(bits, mode)
+	}
+	return newFloat(bits, mode).Quo(big.NewFloat(1), x)
+}
+
+
+// Inv computes the interval of the inverses of the values of the interval. If
+// the interval contains 0, the inverses are unbounded: the entire interval is
+// returned, or a half-infinite one if 0 is one of the bounds. Inverting [0, 0]
+// is an error.
+func Inv(a Interval) (Interval, error) {
+	bits := precision(a)
+	lowerZero := a.lower.Sign() == 0
+	upperZero := a.upper.Sign() == 0
+	switch {
+	case lowerZero && upperZero:
+		return Interval{}, errors.ErrDivisionByZero
+	case lowerZero:
+		return Interval{quotient(a.upper, bits, big.ToNegativeInf), newFloat(bits, big.ToPositiveInf).SetInf(false)}, nil
+	case upperZero:
+		return Interval{newFloat(bits, big.ToNegativeInf).SetInf(true), quotient(a.lower, bits, big.ToPositiveInf)}, nil
+	case a.sign() == 0:
+		return Entire(), nil
+	default:
+		return Interval{quotient(a.upper, bits, big.ToNegativeInf), quotient(a.lower, bits, big.ToPositiveInf)}, nil
+	}
+}
+
+
+// Div computes the interval of the quotients of the values of both intervals.
+// See Inv for the intervals containing 0.
+func Div(a, b Interval) (Interval, error) {
+	if inverse, err := Inv(b); err != nil {
+		return Interval{}, err
+	} else {
+		return Mul(a, inverse), nil
+	}
+}
+
+
+// Abs computes the interval of the absolute values of the values of the interval.
+func Abs(a Interval) Interval {
+	switch {
+	case a.lower.Sign() >= 0:
+		return a
+	case a.upper.Sign() <= 0:
+		return Neg(a)
+	default:
+		bits := precision(a)
+		upper := newFloat(bits, big.ToPositiveInf).Neg(a.lower)
+		if a.upper.Cmp(upper) > 0 {
+			upper.Set(a.upper)
+		}
+		return Interval{newFloat(bits, big.ToNegativeInf), upper}
+	}
+}
+
+
+// Sign computes the interval of the signs of the values of the interval. Since
+// the sign is non-decreasing, only the bounds are evaluated.
+func Sign(a Interval) Interval {
+	return Interval{
+		newFloat(minPrecision, big.ToNegativeInf).SetInt64(int64(a.lower.Sign())),
+		newFloat(minPrecision, big.ToPositiveInf).SetInt64(int64(a.upper.Sign())),
+	}
+}
@@ -9,7 +9,7 @@ import (
 func Abs(value sets.Number) sets.Number {
 	switch c := value.(type) {
 	case *big.Float:
-		return big.NewFloat(0).Abs(c)
+		return new(big.Float).Abs(c)
 	case *big.Rat:
 		return big.NewRat(0, 1).Abs(c)
 	case *big.Int:
@@ -19,3 +19,18 @@ func Abs(value sets.Number) sets.Number {
 	}
 }
 
+
+
+// Sign returns -1, 0 or 1 (as *big.Int) depending on the sign of the value.
+func Sign(value sets.Number) sets.Number {
+	switch c := value.(type) {
+	case *big.Float:
+		return big.NewInt(int64(c.Sign()))
+	case *big.Rat:
+		return big.NewInt(int64(c.Sign()))
+	case *big.Int:
+		return big.NewInt(int64(c.Sign()))
+	default:
+		panic("cannot get the sign of a non-*big.(Int, Float, Rat) value")
+	}
+}
@@ -27,6 +27,7 @@ func Add(terms ...sets.Number) sets.Number {
 	set := sets.BroaderAll(sets.ClosestAll(terms...)...)
 	current := Zero(set)
 	terms = sets.UpCastTo(set, terms...)
+	floatAccumulator(current, terms)
 	for _, term := range terms {
 		inc(current, term)
 	}
@@ -45,11 +46,11 @@ func Sub(minuend sets.Number, subtrahends ...sets.Number) sets.Number {
 	subtrahend = cast[1]
 	switch vm := minuend.(type) {
 	case *big.Int:
-		return vm.Sub(vm, subtrahend.(*big.Int))
+		return big.NewInt(0).Sub(vm, subtrahend.(*big.Int))
 	case *big.Rat:
-		return vm.Sub(vm, subtrahend.(*big.Rat))
+		return big.NewRat(0, 1).Sub(vm, subtrahend.(*big.Rat))
 	case *big.Float:
-		return vm.Sub(vm, subtrahend.(*big.Float))
+		return new(big.Float).Sub(vm, subtrahend.(*big.Float))
 	}
 	return nil
 }
\ No newline at end of file
@@ -1,6 +1,9 @@
 package ops
 
-import "math/big"
+import (
+	"github.com/universe-10th/calculus/sets"
+	"math/big"
+)
 
 
 var oneInt = big.NewInt(1)
@@ -9,3 +12,18 @@ var oneFloat = big.NewFloat(1)
 
 
 
+
+
+// floatAccumulator sets the precision of a float accumulator to the largest
+// one among the given numbers, so operations do not lose precision.
+func floatAccumulator(accumulator sets.Number, numbers []sets.Number) {
+	if value, ok := accumulator.(*big.Float); ok {
+		precision := value.Prec()
+		for _, number := range numbers {
+			if float, ok := number.(*big.Float); ok && float.Prec() > precision {
+				precision = float.Prec()
+			}
+		}
+		value.SetPrec(precision)
+	}
+}
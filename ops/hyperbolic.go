@@ -0,0 +1,111 @@
+package ops
+
+import (
+	"github.com/universe-10th/calculus/sets"
+	"math/big"
+)
+
+
+// The extra bits hyperbolic functions are computed with, besides the ones needed
+// to compensate the cancellation for arguments close to 0.
+const hyperbolicGuardBits = 32
+
+
+// hyperbolicArgument up-casts the argument to a float and tells the precision
+// of the result and the (larger) precision to work with: values close to 0
+// lose as many bits as their (negative) exponent when subtracting exponentials
+// or taking logarithms of values close to 1.
+func hyperbolicArgument(a sets.Number) (*big.Float, uint, uint) {
+	value := sets.UpCastTo(sets.R, a)[0].(*big.Float)
+	precision := value.Prec()
+	working := precision + hyperbolicGuardBits
+	if exponent := value.MantExp(nil); value.Sign() != 0 && exponent < 0 {
+		working += uint(-exponent)
+	}
+	return new(big.Float).SetPrec(working).Set(value), precision, working
+}
+
+
+// exponentials returns e^x and e^-x, computed at the precision of x.
+func exponentials(x *big.Float) (*big.Float, *big.Float) {
+	positive := Exp(x).(*big.Float)
+	negative := new(big.Float).SetPrec(x.Prec()).Quo(big.NewFloat(1), positive)
+	return positive, negative
+}
+
+
+// Sinh computes the hyperbolic sine: (e^x - e^-x) / 2.
+func Sinh(a sets.Number) sets.Number {
+	x, precision, working := hyperbolicArgument(a)
+	positive, negative := exponentials(x)
+	result := new(big.Float).SetPrec(working).Sub(positive, negative)
+	return new(big.Float).SetPrec(precision).Quo(result, big.NewFloat(2))
+}
+
+
+// Cosh computes the hyperbolic cosine: (e^x + e^-x) / 2.
+func Cosh(a sets.Number) sets.Number {
+	x, precision, working := hyperbolicArgument(a)
+	positive, negative := exponentials(x)
+	result := new(big.Float).SetPrec(working).Add(positive, negative)
+	return new(big.Float).SetPrec(precision).Quo(result, big.NewFloat(2))
+}
+
+
+// Tanh computes the hyperbolic tangent: (e^x - e^-x) / (e^x + e^-x). For large
+// arguments (in absolute value), this is computed as +/- 1.
+func Tanh(a sets.Number) sets.Number {
+	x, precision, working := hyperbolicArgument(a)
+	if x.MantExp(nil) > 32 {
+		// e^-2x is below any reasonable precision.
+		return new(big.Float).SetPrec(precision).SetInt64(int64(x.Sign()))
+	}
+	positive, negative := exponentials(x)
+	numerator := new(big.Float).SetPrec(working).Sub(positive, negative)
+	denominator := new(big.Float).SetPrec(working).Add(positive, negative)
+	return new(big.Float).SetPrec(precision).Quo(numerator, denominator)
+}
+
+
+// ArcSinh computes the inverse hyperbolic sine: sign(x) * ln(|x| + sqrt(x^2 + 1)).
+func ArcSinh(a sets.Number) sets.Number {
+	x, precision, working := hyperbolicArgument(a)
+	magnitude := new(big.Float).SetPrec(working).Abs(x)
+	root := new(big.Float).SetPrec(working).Mul(magnitude, magnitude)
+	root.Sqrt(root.Add(root, big.NewFloat(1)))
+	result := Ln(root.Add(root, magnitude)).(*big.Float)
+	if x.Sign() < 0 {
+		result.Neg(result)
+	}
+	return new(big.Float).SetPrec(precision).Set(result)
+}
+
+
+// ArcCosh computes the inverse hyperbolic cosine: ln(x + sqrt(x^2 - 1)). It
+// panics if x < 1.
+func ArcCosh(a sets.Number) sets.Number {
+	x, precision, working := hyperbolicArgument(a)
+	if x.Cmp(big.NewFloat(1)) < 0 {
+		panic("the inverse hyperbolic cosine is only defined in [1, +inf)")
+	}
+	// The precision is increased since x^2 - 1 cancels for x close to 1.
+	working += hyperbolicGuardBits
+	x.SetPrec(working)
+	root := new(big.Float).SetPrec(working).Mul(x, x)
+	root.Sqrt(root.Sub(root, big.NewFloat(1)))
+	return new(big.Float).SetPrec(precision).Set(Ln(root.Add(root, x)).(*big.Float))
+}
+
+
+// ArcTanh computes the inverse hyperbolic tangent: ln((1 + x) / (1 - x)) / 2. It
+// panics if x is not in (-1, 1).
+func ArcTanh(a sets.Number) sets.Number {
+	x, precision, working := hyperbolicArgument(a)
+	if new(big.Float).Abs(x).Cmp(big.NewFloat(1)) >= 0 {
+		panic("the inverse hyperbolic tangent is only defined in (-1, 1)")
+	}
+	numerator := new(big.Float).SetPrec(working).Add(big.NewFloat(1), x)
+	denominator := new(big.Float).SetPrec(working).Sub(big.NewFloat(1), x)
+	result := Ln(numerator.Quo(numerator, denominator)).(*big.Float)
+	return new(big.Float).SetPrec(precision).Quo(result, big.NewFloat(2))
+}
@@ -38,6 +38,7 @@ func Mul(factors ...sets.Number) sets.Number {
 	zero := Zero(set)
 	current := One(set)
 	factors = sets.UpCastTo(set, factors...)
+	floatAccumulator(current, factors)
 	for _, term := range factors {
 		if mul(current, term, zero) {
 			return zero
@@ -51,7 +52,7 @@ func Div(dividend sets.Number, dividers ...sets.Number) sets.Number {
 	if dividers == nil {
 		return dividend
 	}
-	divider := Add(dividers...)
+	divider := Mul(dividers...)
 	set := sets.BroaderAll(sets.ClosestAll(dividend, divider)...)
 	cast := sets.UpCastTo(set, dividend, divider)
 	dividend = cast[0]
@@ -60,9 +61,9 @@ func Div(dividend sets.Number, dividers ...sets.Number) sets.Number {
 	case *big.Int:
 		return big.NewRat(0, 1).SetFrac(vm, divider.(*big.Int))
 	case *big.Rat:
-		return vm.Quo(vm, divider.(*big.Rat))
+		return big.NewRat(0, 1).Quo(vm, divider.(*big.Rat))
 	case *big.Float:
-		return vm.Quo(vm, divider.(*big.Float))
+		return new(big.Float).Quo(vm, divider.(*big.Float))
 	}
 	return nil
 }
@@ -13,7 +13,7 @@ func Neg(a sets.Number) sets.Number {
 	case *big.Rat:
 		return big.NewRat(0, 1).Neg(va)
 	case *big.Float:
-		return big.NewFloat(0).Neg(va)
+		return new(big.Float).Neg(va)
 	default:
 		panic("cannot negate a non-*big.(Int, Float, Rat) value")
 	}
@@ -27,7 +27,7 @@ func Inv(a sets.Number) sets.Number {
 	case *big.Rat:
 		return big.NewRat(0, 1).Inv(va)
 	case *big.Float:
-		return big.NewFloat(0).Quo(oneFloat, va)
+		return new(big.Float).SetPrec(va.Prec()).Quo(oneFloat, va)
 	default:
 		panic("cannot invert a non-*big.(Int, Float, Rat) value")
 	}
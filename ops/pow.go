@@ -42,10 +42,10 @@ func ratIntPow(base *big.Rat, exponent *big.Int) sets.Number {
 	}
 
 	if exponent.Sign() == 0 {
-		return big.NewFloat(1)
+		return big.NewRat(1, 1)
 	}
 	if base.Sign() == 0 {
-		return big.NewFloat(0)
+		return big.NewRat(0, 1)
 	}
 	flag := big.NewInt(0)
 	total := big.NewRat(1, 1)
@@ -69,13 +69,13 @@ func floatIntPow(base *big.Float, exponent *big.Int) sets.Number {
 	}
 
 	if exponent.Sign() == 0 {
-		return big.NewFloat(1)
+		return new(big.Float).SetPrec(base.Prec()).SetInt64(1)
 	}
 	if base.Sign() == 0 {
-		return big.NewFloat(0)
+		return new(big.Float).SetPrec(base.Prec())
 	}
 	flag := big.NewInt(0)
-	total := big.NewFloat(1)
+	total := new(big.Float).SetPrec(base.Prec()).SetInt64(1)
 	factor := base
 	for {
 		if exponent.Cmp(zeroInt) == 0 {
@@ -103,7 +103,7 @@ func Pow(base, exponent sets.Number) sets.Number {
 		case *big.Rat:
 			return bigfloat.Pow(big.NewFloat(0).SetInt(vb), big.NewFloat(0).SetRat(ve))
 		case *big.Float:
-			return bigfloat.Pow(big.NewFloat(0).SetInt(vb), ve)
+			return bigfloat.Pow(new(big.Float).SetPrec(ve.Prec()).SetInt(vb), ve)
 		}
 	case *big.Rat:
 		switch ve := exponent.(type) {
@@ -112,15 +112,15 @@ func Pow(base, exponent sets.Number) sets.Number {
 		case *big.Rat:
 			return bigfloat.Pow(big.NewFloat(0).SetRat(vb), big.NewFloat(0).SetRat(ve))
 		case *big.Float:
-			return bigfloat.Pow(big.NewFloat(0).SetRat(vb), ve)
+			return bigfloat.Pow(new(big.Float).SetPrec(ve.Prec()).SetRat(vb), ve)
 		}
 		return bigfloat.Pow(big.NewFloat(0).SetRat(vb), big.NewFloat(0).SetRat(exponent.(*big.Rat)))
 	case *big.Float:
 		switch ve := exponent.(type) {
 		case *big.Int:
-			return floatIntPow(big.NewFloat(0).Set(vb), big.NewInt(0).Set(ve))
+			return floatIntPow(new(big.Float).Set(vb), big.NewInt(0).Set(ve))
 		case *big.Rat:
-			return bigfloat.Pow(vb, big.NewFloat(0).SetRat(ve))
+			return bigfloat.Pow(vb, new(big.Float).SetPrec(vb.Prec()).SetRat(ve))
 		case *big.Float:
 			return bigfloat.Pow(vb, ve)
 		}
@@ -138,7 +138,7 @@ func Log(power, base sets.Number) sets.Number {
 	cast := sets.UpCastTo(sets.R, base, power)
 	fbase := cast[0].(*big.Float)
 	fpower := cast[1].(*big.Float)
-	return big.NewFloat(0).Quo(bigfloat.Log(fpower), bigfloat.Log(fbase))
+	return new(big.Float).Quo(bigfloat.Log(fpower), bigfloat.Log(fbase))
 }
 
 
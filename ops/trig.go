@@ -3,24 +3,314 @@ package ops
 import (
 	"github.com/universe-10th/calculus/sets"
 	"math/big"
-	"math"
+	"sync"
 )
 
-var float64limit = big.NewFloat(math.MaxFloat64)
 
-func trigf(f func(float64) float64) func(sets.Number) sets.Number {
+// The extra bits trigonometric functions are computed with, to absorb the
+// rounding errors of the series and the reductions of the arguments.
+const trigGuardBits = 64
+
+
+// The most precise value of pi computed so far. Less precise values are
+// rounded from it.
+var piCache struct {
+	sync.Mutex
+	value *big.Float
+}
+
+
+// arcTanOfInverse computes arctan(1/n), for an integer n > 1, by its series:
+// the sum of (-1)^k / ((2k + 1) * n^(2k + 1)).
+func arcTanOfInverse(n int64, precision uint) *big.Float {
+	square := new(big.Float).SetPrec(precision).SetInt64(n * n)
+	power := new(big.Float).SetPrec(precision).Quo(big.NewFloat(1), new(big.Float).SetInt64(n))
+	sum := new(big.Float).SetPrec(precision).Set(power)
+	term := new(big.Float).SetPrec(precision)
+	for k := int64(1); ; k++ {
+		power.Quo(power, square)
+		power.Neg(power)
+		term.Quo(power, new(big.Float).SetInt64(2 * k + 1))
+		if term.Sign() == 0 || term.MantExp(nil) < sum.MantExp(nil) - int(precision) {
+			return sum
+		}
+		sum.Add(sum, term)
+	}
+}
+
+
+// Pi returns pi with the given precision. It is computed by Machin's formula,
+// pi = 16 * arctan(1/5) - 4 * arctan(1/239), and cached.
+func Pi(precision uint) *big.Float {
+	piCache.Lock()
+	defer piCache.Unlock()
+	if piCache.value == nil || piCache.value.Prec() < precision + trigGuardBits {
+		working := precision + 2 * trigGuardBits
+		first := arcTanOfInverse(5, working)
+		second := arcTanOfInverse(239, working)
+		first.SetMantExp(first, 4)
+		second.SetMantExp(second, 2)
+		piCache.value = first.Sub(first, second)
+	}
+	return new(big.Float).SetPrec(precision).Set(piCache.value)
+}
+
+
+// trigArgument up-casts the argument to a float and tells the precision of the
+// result and the (larger) precision to work with. It panics on infinite values.
+func trigArgument(a sets.Number) (*big.Float, uint, uint) {
+	value := sets.UpCastTo(sets.R, a)[0].(*big.Float)
+	if value.IsInf() {
+		panic("trigonometric functions are undefined for infinite values")
+	}
+	precision := value.Prec()
+	return new(big.Float).SetPrec(precision + trigGuardBits).Set(value), precision, precision + trigGuardBits
+}
+
+
+// reduce computes r = x - k * pi/2, with k the closest integer to x / (pi/2),
+// and returns r and k mod 4 (the quadrant). Pi takes as many extra bits as the
+// integer part of x, and as many more as the leading zeros of r (which cancels
+// when x is close to a multiple of pi/2), so r keeps the working precision.
+func reduce(x *big.Float, working uint) (*big.Float, int) {
+	integer := uint(0)
+	if exponent := x.MantExp(nil); exponent > 0 {
+		integer = uint(exponent)
+	}
+	extra := integer
+	for {
+		precision := working + extra
+		halfPi := Pi(precision)
+		halfPi.SetMantExp(halfPi, -1)
+		quotient := new(big.Float).SetPrec(precision).Quo(x, halfPi)
+		if quotient.Sign() < 0 {
+			quotient.Sub(quotient, big.NewFloat(0.5))
+		} else {
+			quotient.Add(quotient, big.NewFloat(0.5))
+		}
+		k, _ := quotient.Int(nil)
+		reduced := new(big.Float).SetPrec(precision).Mul(halfPi, new(big.Float).SetInt(k))
+		reduced.Sub(x, reduced)
+		if k.Sign() == 0 {
+			return reduced, 0
+		} else if reduced.Sign() == 0 {
+			extra += working
+		} else if lost := -reduced.MantExp(nil); lost > 0 && extra < integer + uint(lost) {
+			extra = integer + uint(lost)
+		} else {
+			return reduced, int(new(big.Int).And(k, big.NewInt(3)).Int64())
+		}
+	}
+}
+
+
+// sinCosSeries computes the sine and cosine of a reduced argument (|r| <= pi/4)
+// by their Taylor series.
+func sinCosSeries(r *big.Float, working uint) (*big.Float, *big.Float) {
+	square := new(big.Float).SetPrec(working).Mul(r, r)
+	sin := new(big.Float).SetPrec(working).Set(r)
+	cos := new(big.Float).SetPrec(working).SetInt64(1)
+	sinTerm := new(big.Float).SetPrec(working).Set(r)
+	cosTerm := new(big.Float).SetPrec(working).SetInt64(1)
+	for n := int64(1); ; n += 2 {
+		// The terms are multiplied by -r^2 / (n * (n + 1)) and -r^2 / ((n + 1) * (n + 2)).
+		cosTerm.Mul(cosTerm, square)
+		cosTerm.Quo(cosTerm, new(big.Float).SetInt64(-n * (n + 1)))
+		sinTerm.Mul(sinTerm, square)
+		sinTerm.Quo(sinTerm, new(big.Float).SetInt64(-(n + 1) * (n + 2)))
+		if sinTerm.Sign() == 0 || sinTerm.MantExp(nil) < sin.MantExp(nil) - int(working) &&
+			cosTerm.MantExp(nil) < -int(working) {
+			return sin, cos
+		}
+		sin.Add(sin, sinTerm)
+		cos.Add(cos, cosTerm)
+	}
+}
+
+
+// sinCos computes the sine and cosine of a number, in the working precision, and
+// tells the precision of the results.
+func sinCos(a sets.Number) (*big.Float, *big.Float, uint) {
+	x, precision, working := trigArgument(a)
+	if x.Sign() == 0 {
+		return new(big.Float).SetPrec(working), new(big.Float).SetPrec(working).SetInt64(1), precision
+	}
+	reduced, quadrant := reduce(x, working)
+	sin, cos := sinCosSeries(reduced, working)
+	switch quadrant {
+	case 1:
+		return cos, sin.Neg(sin), precision
+	case 2:
+		return sin.Neg(sin), cos.Neg(cos), precision
+	case 3:
+		return cos.Neg(cos), sin, precision
+	default:
+		return sin, cos, precision
+	}
+}
+
+
+// Sin computes the sine, keeping the precision of the argument.
+func Sin(a sets.Number) sets.Number {
+	sin, _, precision := sinCos(a)
+	return new(big.Float).SetPrec(precision).Set(sin)
+}
+
+
+// Cos computes the cosine, keeping the precision of the argument.
+func Cos(a sets.Number) sets.Number {
+	_, cos, precision := sinCos(a)
+	return new(big.Float).SetPrec(precision).Set(cos)
+}
+
+
+// Tan computes the tangent, keeping the precision of the argument. It panics
+// if the cosine is 0.
+func Tan(a sets.Number) sets.Number {
+	sin, cos, precision := sinCos(a)
+	if cos.Sign() == 0 {
+		panic("the tangent is undefined for the value")
+	}
+	return new(big.Float).SetPrec(precision).Quo(sin, cos)
+}
+
+
+// reciprocal inverts the result of a trigonometric function, panicking if it is 0
+// (where the reciprocal function has a vertical asymptote).
+func reciprocal(f func(sets.Number) sets.Number) func(sets.Number) sets.Number {
 	return func(a sets.Number) sets.Number {
-		ra := sets.UpCastTo(sets.R, a)[0].(*big.Float)
-		if big.NewFloat(0).Abs(ra).Cmp(float64limit) > 0 {
-			panic("the float value exceeds 64 bits - trigonometric functions use that precision")
+		if value := f(a); IsZero(value) {
+			panic("the reciprocal trigonometric function is undefined for the value")
 		} else {
-			value, _ := ra.Float64()
-			return big.NewFloat(f(value))
+			return Inv(value)
 		}
 	}
 }
 
 
-var Sin = trigf(math.Sin)
-var Cos = trigf(math.Cos)
-var Tan = trigf(math.Tan)
+var Sec = reciprocal(Cos)
+var Csc = reciprocal(Sin)
+var Cot = reciprocal(Tan)
+
+
+// arcTan computes the arc tangent in the working precision. Arguments beyond 1
+// (in absolute value) are reflected: arctan(x) = +/-pi/2 - arctan(1/x), and the
+// others are halved, arctan(x) = 2 * arctan(x / (1 + sqrt(1 + x^2))), until
+// they are small enough for the Taylor series to converge fast.
+func arcTan(x *big.Float, working uint) *big.Float {
+	if x.Sign() == 0 {
+		return new(big.Float).SetPrec(working)
+	} else if x.IsInf() || new(big.Float).Abs(x).Cmp(big.NewFloat(1)) > 0 {
+		halfPi := Pi(working)
+		halfPi.SetMantExp(halfPi, -1)
+		if x.Sign() < 0 {
+			halfPi.Neg(halfPi)
+		}
+		if x.IsInf() {
+			return halfPi
+		}
+		return halfPi.Sub(halfPi, arcTan(new(big.Float).SetPrec(working).Quo(big.NewFloat(1), x), working))
+	}
+	value := new(big.Float).SetPrec(working).Set(x)
+	root := new(big.Float).SetPrec(working)
+	halvings := 0
+	for value.MantExp(nil) > -8 {
+		root.Mul(value, value)
+		root.Sqrt(root.Add(root, big.NewFloat(1)))
+		value.Quo(value, root.Add(root, big.NewFloat(1)))
+		halvings++
+	}
+	square := new(big.Float).SetPrec(working).Mul(value, value)
+	power := new(big.Float).SetPrec(working).Set(value)
+	sum := new(big.Float).SetPrec(working).Set(value)
+	term := new(big.Float).SetPrec(working)
+	for k := int64(1); ; k++ {
+		power.Mul(power, square)
+		power.Neg(power)
+		term.Quo(power, new(big.Float).SetInt64(2 * k + 1))
+		if term.Sign() == 0 || term.MantExp(nil) < sum.MantExp(nil) - int(working) {
+			break
+		}
+		sum.Add(sum, term)
+	}
+	return sum.SetMantExp(sum, halvings)
+}
+
+
+// unitArgument prepares the argument of an inverse trigonometric function only
+// defined in [-1, 1], panicking if it is out of that range. It also returns
+// sqrt(1 - x^2), computed as sqrt((1 - x) * (1 + x)) to avoid cancellation.
+func unitArgument(a sets.Number) (*big.Float, *big.Float, uint, uint) {
+	if Cmp(Abs(a), big.NewInt(1)) > 0 {
+		panic("the value must be in [-1, 1]")
+	}
+	x, precision, working := trigArgument(a)
+	complement := new(big.Float).SetPrec(working).Sub(big.NewFloat(1), x)
+	complement.Mul(complement, new(big.Float).SetPrec(working).Add(big.NewFloat(1), x))
+	return x, complement.Sqrt(complement), precision, working
+}
+
+
+// ArcSin computes the arc sine, in [-pi/2, pi/2], keeping the precision of the
+// argument: arctan2(x, sqrt(1 - x^2)). It panics if the value is not in [-1, 1].
+func ArcSin(a sets.Number) sets.Number {
+	x, complement, precision, working := unitArgument(a)
+	return new(big.Float).SetPrec(precision).Set(arcTan2(x, complement, working))
+}
+
+
+// ArcCos computes the arc cosine, in [0, pi], keeping the precision of the
+// argument: arctan2(sqrt(1 - x^2), x). It panics if the value is not in [-1, 1].
+func ArcCos(a sets.Number) sets.Number {
+	x, complement, precision, working := unitArgument(a)
+	return new(big.Float).SetPrec(precision).Set(arcTan2(complement, x, working))
+}
+
+
+// ArcTan computes the arc tangent, in (-pi/2, pi/2), keeping the precision of
+// the argument.
+func ArcTan(a sets.Number) sets.Number {
+	value := sets.UpCastTo(sets.R, a)[0].(*big.Float)
+	precision := value.Prec()
+	return new(big.Float).SetPrec(precision).Set(arcTan(value, precision + trigGuardBits))
+}
+
+
+// arcTan2 computes the angle of the (x, y) point in the working precision. The
+// point must not be the origin.
+func arcTan2(y, x *big.Float, working uint) *big.Float {
+	if x.Sign() == 0 {
+		halfPi := Pi(working)
+		halfPi.SetMantExp(halfPi, -1)
+		if y.Sign() < 0 {
+			halfPi.Neg(halfPi)
+		}
+		return halfPi
+	}
+	result := arcTan(new(big.Float).SetPrec(working).Quo(y, x), working)
+	if x.Sign() < 0 {
+		// The angle is in the second or third quadrant: add or subtract pi.
+		if y.Sign() < 0 {
+			result.Sub(result, Pi(working))
+		} else {
+			result.Add(result, Pi(working))
+		}
+	}
+	return result
+}
+
+
+// ArcTan2 computes the angle of the (x, y) point, in (-pi, pi], keeping the
+// largest precision of both values. It panics on (0, 0).
+func ArcTan2(y, x sets.Number) sets.Number {
+	if IsZero(x) && IsZero(y) {
+		panic("the angle of the origin is undefined")
+	}
+	cast := sets.UpCastTo(sets.R, y, x)
+	fy, fx := cast[0].(*big.Float), cast[1].(*big.Float)
+	precision := fy.Prec()
+	if fx.Prec() > precision {
+		precision = fx.Prec()
+	}
+	return new(big.Float).SetPrec(precision).Set(arcTan2(fy, fx, precision + trigGuardBits))
+}
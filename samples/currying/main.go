@@ -22,4 +22,7 @@ func main() {
 	fmt.Printf("Currying expression %s with values (X=1, Y=2)...\n", value)
 	result, err := value.Curry(Arguments{X: 1, Y: 2})
 	fmt.Printf("Result: %s, %v\n", result, err)
+	fmt.Printf("Substituting Y with (X + 1) and Z with 2 * X in expression %s...\n", value)
+	substituted, err := value.Substitute(Substitutions{Y: Add(X, Num(1)), Z: Mul(Num(2), X)})
+	fmt.Printf("Result: %s, %v\n", substituted, err)
 }
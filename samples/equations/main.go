@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+	"github.com/universe-10th/calculus/core/support/diff"
+	. "github.com/universe-10th/calculus/expressions"
+	"github.com/universe-10th/calculus/expressions/goal-seek"
+)
+
+func main() {
+	A, B := Var("A"), Var("B")
+	factory := goal_seek.NRGoalSeekFactory(func(arguments Arguments) (*big.Float, *big.Float, uint32, uint32) {
+		return big.NewFloat(0.5), diff.Epsilon(12), 100, 10
+	})
+	for _, equation := range []Equation{
+		{Left: Add(B, Mul(X, A)), Right: Y},
+		{Left: Exp(Mul(Num(2), X)), Right: Y},
+		{Left: Log(Num(10), Inverse(X)), Right: Num(2)},
+		{Left: Mul(A, Sin(X)), Right: Y},
+		{Left: ArcTan2(Y, X), Right: Num(0.5)},
+		{Left: Add(X, Mul(Y, Sin(X))), Right: Num(1)},
+	} {
+		if solution, err := Solve(equation, X, factory); err != nil {
+			fmt.Printf("%s could not be solved for X: %s\n", equation, err)
+		} else {
+			fmt.Printf("%s is solved by X = %s\n", equation, solution)
+			if value, err := solution.Evaluate(Arguments{A: 2, B: 1, Y: 3}.Wrap()); err == nil {
+				fmt.Printf("... which, for A = 2, B = 1 and Y = 3, is: %v\n", value)
+			}
+		}
+	}
+}
@@ -27,4 +27,50 @@ func main() {
 		}.Wrap())
 		fmt.Println("Evaluating derivative with (X=1, Y=2, Z=4): ", result, err)
 	}
+	if result, derivatives, err := EvaluateDual(value, Arguments{
+		X: 1, Y: 2, Z: 4,
+	}.Wrap(), []Variable{X, Y, Z}); err != nil {
+		fmt.Println("Error when evaluating with dual numbers", err)
+	} else {
+		fmt.Println("Evaluating with dual numbers, with (X=1, Y=2, Z=4): ", result, "- derivatives (X, Y, Z): ", derivatives)
+	}
+	if result, gradient, err := EvaluateGradient(value, Arguments{
+		X: 1, Y: 2, Z: 4,
+	}.Wrap()); err != nil {
+		fmt.Println("Error when evaluating the gradient", err)
+	} else {
+		fmt.Println("Evaluating with the gradient, with (X=1, Y=2, Z=4): ", result, "- gradient: ", gradient)
+	}
+	integrable := Mul(Pow(X, Num(2)), Exp(Mul(Num(2), X)))
+	if integral, err := Integrate(integrable, X); err != nil {
+		fmt.Println("Error when integrating", err)
+	} else {
+		fmt.Println("Integral of", integrable, "display: ", integral.String())
+		derivative, err := integral.Derivative(X)
+		fmt.Println("Deriving the integral back: ", derivative, err)
+	}
+	expandable := Cos(Mul(X, Y))
+	if polynomial, remainder, err := Series(expandable, X, 0, 4); err != nil {
+		fmt.Println("Error when expanding", err)
+	} else {
+		fmt.Println("Series of", expandable, "up to X^4: ", polynomial, "- error estimate: ", remainder)
+	}
+	field := Add(Mul(X, X, Y), Sin(Z), Ln(Y))
+	if hessian, err := Hessian(field, SortedVariables(field)); err != nil {
+		fmt.Println("Error when building the hessian", err)
+	} else {
+		gradient, _ := Gradient(field, SortedVariables(field))
+		fmt.Println("Gradient of", field, "over", SortedVariables(field), "display: ", gradient)
+		fmt.Println("Hessian of", field, "display: ", hessian)
+		values, err := hessian.Evaluate(Arguments{X: 1, Y: 2, Z: 4}.Wrap())
+		fmt.Println("Evaluating hessian with (X=1, Y=2, Z=4): ", values, err)
+	}
+	guarded := Add(Ln(Sub(X, Num(1))), Inverse(Sub(Y, X)), Tan(Z))
+	if conditions, err := Domain(guarded); err != nil {
+		fmt.Println("Error when analyzing the domain", err)
+	} else {
+		fmt.Println("Domain of", guarded, "display: ", conditions)
+		fmt.Println("Checking the domain with (X=2, Y=2, Z=4): ", conditions.Check(Arguments{X: 2, Y: 2, Z: 4}.Wrap()))
+		fmt.Println("Checking the domain with (X=2, Y=3, Z=4): ", conditions.Check(Arguments{X: 2, Y: 3, Z: 4}.Wrap()))
+	}
 }
\ No newline at end of file
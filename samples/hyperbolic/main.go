@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+	. "github.com/universe-10th/calculus/expressions"
+)
+
+
+// A hanging cable (a catenary) of parameter A: Y = A * cosh(X / A).
+var A = Var("A")
+
+
+func Catenary() Expression {
+	return Mul(A, Cosh(Div(X, A)))
+}
+
+
+func main() {
+	cable := Catenary()
+	fmt.Println("Catenary display: ", cable)
+	if slope, err := cable.Derivative(X); err != nil {
+		fmt.Println("Error when deriving", err)
+	} else {
+		fmt.Println("Slope display: ", slope)
+		value, err := slope.Evaluate(Arguments{A: 2, X: 3}.Wrap())
+		fmt.Println("Evaluating slope with (A=2, X=3): ", value, err)
+	}
+	if position, err := Solve(Equation{Left: cable, Right: Y}, X, nil); err != nil {
+		fmt.Println("Error when solving", err)
+	} else {
+		fmt.Println("Position for a given height display: ", position)
+		value, err := position.Evaluate(Arguments{A: 2, Y: 5}.Wrap())
+		fmt.Println("Evaluating position with (A=2, Y=5): ", value, err)
+		conditions, err := Domain(position)
+		fmt.Println("Domain of the position: ", conditions, err)
+		fmt.Println("Checking the domain with (A=2, Y=1): ", conditions.Check(Arguments{A: 2, Y: 1}.Wrap()))
+	}
+	// Relativistic velocities (as fractions of the speed of light) are added by
+	// adding their rapidities: arctanh(v).
+	combined := Tanh(Add(ArcTanh(X), ArcTanh(Y)))
+	fmt.Println("Relativistic addition display: ", combined)
+	half := new(big.Float).SetPrec(200).SetFloat64(0.5)
+	value, err := combined.Evaluate(Arguments{X: half, Y: half})
+	fmt.Println("Evaluating relativistic addition with (X=0.5, Y=0.5), 200 bits: ", value, err)
+	value, err = combined.Evaluate(Arguments{X: 1, Y: 0.5}.Wrap())
+	fmt.Println("Evaluating relativistic addition with (X=1, Y=0.5): ", value, err)
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+	"github.com/universe-10th/calculus/core/support/diff"
+	. "github.com/universe-10th/calculus/expressions"
+	"github.com/universe-10th/calculus/expressions/quadrature"
+)
+
+func main() {
+	provider := func(arguments Arguments) (*big.Float, uint32) {
+		return diff.Epsilon(15), 100000
+	}
+	gaussian := Exp(Negated(Mul(Pow(X, Num(2)), Y)))
+	for _, method := range []quadrature.Method{quadrature.AdaptiveSimpson, quadrature.Romberg, quadrature.GaussLegendre} {
+		integral := quadrature.Quadrature(method, gaussian, X, Num(0), Z, provider)
+		if value, err := integral.Evaluate(Arguments{Y: 1, Z: 4}.Wrap()); err == nil {
+			fmt.Printf("%s, for Y = 1 and Z = 4: %s\n", integral, value.(*big.Float).Text('g', 20))
+		} else {
+			fmt.Printf("%s, for Y = 1 and Z = 4, failed: %s\n", integral, err)
+		}
+	}
+	RegisterIntegrationAlgorithmFactory("gauss-legendre", quadrature.QuadratureFactory(quadrature.GaussLegendre, provider))
+	if integral, err := NamedIntegral(gaussian, X, Num(0), Z, "gauss-legendre"); err == nil {
+		if derivative, err := integral.Derivative(Y); err == nil {
+			fmt.Printf("d/dY %s = %s\n", integral, derivative)
+			if value, err := derivative.Evaluate(Arguments{Y: 1, Z: 4}.Wrap()); err == nil {
+				fmt.Printf("... which, for Y = 1 and Z = 4, is: %s\n", value.(*big.Float).Text('g', 20))
+			}
+		}
+		if data, err := Marshal(integral); err == nil {
+			fmt.Printf("Encoded: %s\n", data)
+		}
+	}
+}
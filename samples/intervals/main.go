@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+	. "github.com/universe-10th/calculus/expressions"
+	"github.com/universe-10th/calculus/intervals"
+)
+
+// candidates splits [lower, upper] into pieces, discarding the ones where the
+// expression cannot be zero: the remaining ones are the only ones that may
+// contain roots, so they can be used to bracket a goal-seek.
+func candidates(expression Expression, lower, upper float64, pieces int) []intervals.Interval {
+	result := []intervals.Interval{}
+	width := (upper - lower) / float64(pieces)
+	for index := 0; index < pieces; index++ {
+		piece, _ := intervals.New(lower + width * float64(index), lower + width * float64(index + 1))
+		if value, err := EvaluateInterval(expression, IntervalArguments{X: piece}); err != nil {
+			fmt.Printf("%s over %s: %s\n", expression, piece, err)
+		} else if value.Contains(big.NewInt(0)) {
+			result = append(result, piece)
+		}
+	}
+	return result
+}
+
+func main() {
+	for _, expression := range []Expression{
+		Add(Pow(X, Num(3)), Mul(Num(-2), X), Num(-5)),
+		Add(Mul(X, Exp(X)), Num(-1)),
+		Mul(Sin(X), Ln(Add(X, Num(2)))),
+		Inverse(Add(X, Num(-1))),
+	} {
+		if value, err := Bounds(expression, map[Variable][2]interface{}{X: {-1, 3}}); err != nil {
+			fmt.Printf("%s over [-1, 3]: %s\n", expression, err)
+		} else {
+			fmt.Printf("%s over [-1, 3] is within %s\n", expression, value)
+		}
+		fmt.Printf("... and its roots may only be in: %v\n", candidates(expression, -1, 3, 16))
+	}
+	ratio := Mul(Sin(X), Inverse(Y))
+	if value, err := Bounds(ratio, map[Variable][2]interface{}{X: {0.5, 1}, Y: {2, big.NewRat(5, 2)}}); err == nil {
+		fmt.Printf("%s over X in [0.5, 1], Y in [2, 5/2] is within %s\n", ratio, value)
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+	"github.com/universe-10th/calculus/core/linear"
+	. "github.com/universe-10th/calculus/expressions"
+)
+
+func main() {
+	systems := [][]Equation{
+		{
+			{Left: Add(Mul(Num(2), X), Y, Negated(Z)), Right: Num(8)},
+			{Left: Add(Mul(Num(-3), X), Negated(Y), Mul(Num(2), Z)), Right: Num(-11)},
+			{Left: Add(Mul(Num(-2), X), Y, Mul(Num(2), Z)), Right: Num(-3)},
+		},
+		{
+			{Left: Add(X, Y, Z), Right: Num(1)},
+			{Left: Add(Mul(Num(2), X), Div(Y, Num(3))), Right: Num(2)},
+		},
+		{
+			{Left: Add(X, Y), Right: Num(1)},
+			{Left: Add(X, Y), Right: Num(2)},
+		},
+	}
+	for _, system := range systems {
+		fmt.Printf("Solving %v...\n", system)
+		if solution, err := linear.Solve(system, nil); err != nil {
+			fmt.Printf("Error: %s\n", err)
+		} else {
+			fmt.Printf("Kind of solution: %s\n", solution.Kind)
+			if solution.Kind != linear.None {
+				fmt.Printf("Solution: %v, free variables: %v\n", solution.Parametric, solution.Free)
+			}
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	. "github.com/universe-10th/calculus/expressions"
+	"fmt"
+)
+
+
+func main() {
+	for _, source := range []string{
+		"X^3 * ln(X * 4) * tan(X)",
+		"(X + Y) / 2 - 3/4 * Y^-1",
+		"Round(X * 1.5, 0) + (X + 1)!",
+		"X * (Y + \n  $)",
+	} {
+		if expression, err := Parse(source); err != nil {
+			fmt.Printf("Parsing %q failed: %s\n", source, err)
+		} else {
+			result, err := expression.Evaluate(Arguments{X: 3, Y: 2}.Wrap())
+			fmt.Printf("Parsed %q as %s, evaluating with (X=3, Y=2): %v %v\n", source, expression, result, err)
+			fmt.Printf("LaTeX: %s\n", LaTeX(expression))
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	. "github.com/universe-10th/calculus/expressions"
+)
+
+func main() {
+	Income, Threshold := Var("Income"), Var("Threshold")
+	// A progressive tax: nothing up to the threshold, 10% of the income above
+	// it (up to 5000), and 20% of the income above 5000.
+	tax := Piecewise([]Branch{
+		{Condition: LessOrEqual(Income, Threshold), Value: Num(0)},
+		{Condition: LessOrEqual(Income, Num(5000)), Value: Mul(Num(0.1), Sub(Income, Threshold))},
+	}, Add(Mul(Num(0.1), Sub(Num(5000), Threshold)), Mul(Num(0.2), Sub(Income, Num(5000)))))
+	fmt.Println("Tax:", tax)
+	if marginal, err := tax.Derivative(Income); err != nil {
+		fmt.Println("Error when deriving the tax", err)
+	} else {
+		fmt.Println("Marginal rate:", marginal)
+	}
+	for _, income := range []int{800, 3000, 12000} {
+		value, err := tax.Evaluate(Arguments{Income: income, Threshold: 1000}.Wrap())
+		fmt.Printf("Tax for an income of %d: %v %v\n", income, value, err)
+	}
+	if curried, err := tax.Curry(Arguments{Income: 3000}.Wrap()); err != nil {
+		fmt.Println("Error when currying the tax", err)
+	} else {
+		fmt.Println("Tax for an income of 3000, by threshold:", curried)
+	}
+	bonus := Clamp(Mul(Num(0.05), Max(Sub(Income, Num(2000)), Num(0))), Num(0), Num(250))
+	fmt.Println("Bonus:", bonus)
+	derivative, err := bonus.Derivative(Income)
+	fmt.Println("Bonus derivative:", derivative, err)
+	for _, income := range []int{1500, 4000, 9000} {
+		value, _ := bonus.Evaluate(Arguments{Income: income}.Wrap())
+		fmt.Printf("Bonus for an income of %d: %v\n", income, value)
+	}
+	deviation := Abs(Sub(Income, Threshold))
+	if derivative, err := deviation.Derivative(Income); err != nil {
+		fmt.Println("Error when deriving the deviation", err)
+	} else {
+		fmt.Println("Deviation:", deviation, "- derivative:", derivative)
+		for _, income := range []int{500, 1000, 1500} {
+			value, err := derivative.Evaluate(Arguments{Income: income, Threshold: 1000}.Wrap())
+			fmt.Printf("Deviation derivative for an income of %d: %v %v\n", income, value, err)
+		}
+	}
+	fmt.Println("LaTeX:", LaTeX(tax))
+}
@@ -2,7 +2,10 @@ package main
 
 import (
 	"fmt"
+	"math/big"
+	"github.com/universe-10th/calculus/core/goals"
 	"github.com/universe-10th/calculus/core/support"
+	"github.com/universe-10th/calculus/core/support/diff"
 	. "github.com/universe-10th/calculus/expressions"
 )
 
@@ -13,4 +16,22 @@ func main() {
 	fmt.Printf("A polynomial expression: %s\n", support.PolynomialExpression(X, 2, -3, 5, -7))
 	fmt.Printf("A polynomial expression: %s\n", support.PolynomialExpression(X, 2, -3, 5, -7, 11))
 	fmt.Printf("A polynomial expression: %s\n", support.PolynomialExpression(X, 2, -3, 5, -7, 11, -13))
+	cube := support.NewPolynomial(1, -3, 3, -1)
+	square := support.NewPolynomial(1, 0, -1)
+	quotient, remainder, _ := cube.DivMod(square)
+	fmt.Printf("(%s) / (%s) = %s, remainder: %s\n", cube, square, quotient, remainder)
+	fmt.Printf("gcd(%s, %s) = %s\n", cube, square, cube.GCD(square))
+	fmt.Printf("(%s)' = %s\n", cube, cube.Derivative())
+	fmt.Printf("(%s)(X + 1) = %s\n", square, square.Compose(support.NewPolynomial(1, 1)))
+	fmt.Printf("(%s)(1/2) = %s\n", cube, cube.Evaluate(big.NewRat(1, 2)))
+	if recognized, err := support.PolynomialFromExpression(Mul(Add(X, Num(1)), Sub(X, Num(1))), X); err == nil {
+		fmt.Printf("(X + 1) * (X - 1) is the polynomial: %s\n", recognized)
+	}
+	withRoots := support.NewPolynomial(1, -1, -3, 3, 2, -2)
+	if roots, err := goals.PolynomialRoots(withRoots, diff.Epsilon(20)); err == nil {
+		fmt.Printf("The roots of %s are:\n", withRoots)
+		for _, root := range roots {
+			fmt.Printf("- %v (multiplicity: %d, exact: %v)\n", root.Value, root.Multiplicity, root.Exact)
+		}
+	}
 }
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"github.com/universe-10th/calculus/sets"
+	. "github.com/universe-10th/calculus/expressions"
+)
+
+func main() {
+	rules := DefaultRewriteRules()
+	for _, expression := range []Expression{
+		Ln(Exp(Add(X, Y))),
+		Mul(Num(2), Exp(X), Sin(Y), Exp(Y)),
+		Pow(Pow(X, Y), Num(3)),
+		Log(X, Pow(X, Add(Y, Num(1)))),
+		Exp(Mul(Y, Ln(Z))),
+	} {
+		if rewritten, err := Rewrite(expression, rules, 100); err != nil {
+			fmt.Printf("%s could not be rewritten: %s\n", expression, err)
+		} else {
+			fmt.Printf("%s => %s\n", expression, rewritten)
+		}
+	}
+	// A custom rule: sin(a)^2 + cos(a)^2 = 1.
+	a := Var("a")
+	if pythagorean, err := NewRewriteRule(
+		"pythagorean", Add(Pow(Sin(a), Num(2)), Pow(Cos(a), Num(2))), Num(1), []Variable{a},
+	); err == nil {
+		expression := Add(Pow(Cos(X), Num(2)), Y, Pow(Sin(X), Num(2)))
+		rewritten, err := Rewrite(expression, []RewriteRule{pythagorean}, 100)
+		fmt.Printf("%s => %s, %v\n", expression, rewritten, err)
+	}
+	// A guarded rule: x^n * x = x^(n + 1), only for integer n.
+	x, n := Var("x"), Var("n")
+	if raise, err := NewRewriteRule(
+		"raise", Mul(Pow(x, n), x), Pow(x, Add(n, Num(1))), []Variable{x, n}, NumericGuard(n, sets.Z),
+	); err == nil {
+		for _, expression := range []Expression{Mul(Pow(X, Num(2)), X), Mul(Pow(X, Y), X)} {
+			rewritten, err := Rewrite(expression, []RewriteRule{raise}, 100)
+			fmt.Printf("%s => %s, %v\n", expression, rewritten, err)
+		}
+	}
+}
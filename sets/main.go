@@ -95,7 +95,8 @@ func Wrap(value interface{}) (Number, Set) {
 func Clone(value interface{}) Number {
 	switch c := value.(type) {
 	case *big.Float:
-		return big.NewFloat(0).Set(c)
+		// A zero-precision float takes the precision of the copied one.
+		return new(big.Float).Set(c)
 	case *big.Rat:
 		return big.NewRat(0, 1).Set(c)
 	case *big.Int: